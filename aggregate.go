@@ -0,0 +1,76 @@
+package gorax
+
+// Monoid is used to aggregate the values of a subtree. Combine must be associative with Identity as identity element,
+// it is called with the aggregates in lexicographical order of their keys.
+type Monoid interface {
+	// Identity returns the aggregate of an empty subtree.
+	Identity() interface{}
+
+	// Combine returns the aggregate of two aggregates.
+	Combine(a, b interface{}) interface{}
+
+	// FromValue returns the aggregate of a single value.
+	FromValue(value interface{}) interface{}
+}
+
+// Aggregate returns the aggregate of all values under a prefix in O(k), using the aggregates cached in the nodes.
+// Returns nil if the Tree has no Monoid. If the Tree has a separator, only the prefix itself and the keys of the
+// segments below it are aggregated.
+func (t *Tree) Aggregate(prefix string) interface{} {
+	if t.monoid == nil {
+		return nil
+	}
+
+	t.expire()
+
+	prefix = t.normalizeKey(prefix)
+	if t.isSegmentPrefix(prefix) {
+		return t.aggregatePrefix(prefix)
+	}
+
+	ret := t.monoid.Identity()
+	if current := t.lookup(prefix); current != nil {
+		ret = t.monoid.FromValue(current.getValue())
+	}
+
+	return t.monoid.Combine(ret, t.aggregatePrefix(t.segmentPrefix(prefix)))
+}
+
+func (t *Tree) aggregatePrefix(prefix string) interface{} {
+	current, _ := t.subtree(prefix)
+	if current == nil {
+		return t.monoid.Identity()
+	}
+
+	return t.aggregate(current)
+}
+
+// aggregate returns the aggregate of a node and updates it first if it was invalidated.
+func (t *Tree) aggregate(n *node) interface{} {
+	c := n.caches()
+	if !c.aggregated {
+		ret := t.monoid.Identity()
+		if n.isKey() {
+			ret = t.monoid.FromValue(n.getValue())
+		}
+		for _, child := range n.children {
+			ret = t.monoid.Combine(ret, t.aggregate(child))
+		}
+
+		c.aggregate = ret
+		c.aggregated = true
+	}
+
+	return c.aggregate
+}
+
+// invalidate marks the aggregate, the hash and the key count of a node as outdated, it is used as find function on the path of a
+// mutation.
+func invalidate(_ string, n *node) bool {
+	if n.cache != nil {
+		n.cache.aggregated = false
+		n.cache.hashed = false
+		n.cache.counted = false
+	}
+	return false
+}
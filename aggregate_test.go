@@ -0,0 +1,131 @@
+package gorax_test
+
+import (
+	"math/rand"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/snorwin/gorax"
+)
+
+// sum is a Monoid summing up integer values.
+type sum struct{}
+
+func (sum) Identity() interface{} {
+	return 0
+}
+
+func (sum) Combine(a, b interface{}) interface{} {
+	return a.(int) + b.(int)
+}
+
+func (sum) FromValue(value interface{}) interface{} {
+	if value == nil {
+		return 0
+	}
+
+	return value.(int)
+}
+
+// concat is a non-commutative Monoid concatenating string values.
+type concat struct{}
+
+func (concat) Identity() interface{} {
+	return ""
+}
+
+func (concat) Combine(a, b interface{}) interface{} {
+	return a.(string) + b.(string)
+}
+
+func (concat) FromValue(value interface{}) interface{} {
+	return value.(string)
+}
+
+var _ = Describe("Tree", func() {
+	Context("Aggregate", func() {
+		It("should_return_nil_without_monoid", func() {
+			Ω(gorax.New().Aggregate("")).Should(BeNil())
+		})
+		It("should_return_identity_if_empty", func() {
+			Ω(gorax.New(gorax.WithAggregate(sum{})).Aggregate("foo")).Should(Equal(0))
+		})
+		It("should_aggregate_prefix", func() {
+			t := gorax.FromMap(map[string]interface{}{
+				"foo":    1,
+				"foobar": 2,
+				"foofoo": 4,
+				"bar":    8,
+				"fo":     nil,
+			}, gorax.WithAggregate(sum{}))
+
+			Ω(t.Aggregate("")).Should(Equal(15))
+			Ω(t.Aggregate("foo")).Should(Equal(7))
+			Ω(t.Aggregate("foob")).Should(Equal(2))
+			Ω(t.Aggregate("jin")).Should(Equal(0))
+
+			t.Insert("foobar", 16)
+			Ω(t.Aggregate("foo")).Should(Equal(21))
+
+			t.Delete("foo")
+			Ω(t.Aggregate("foo")).Should(Equal(20))
+
+			t.DeletePrefix("foof")
+			Ω(t.Aggregate("")).Should(Equal(24))
+		})
+		It("should_aggregate_in_lexicographical_order", func() {
+			t := gorax.FromMap(map[string]interface{}{
+				"c":  "c",
+				"a":  "a",
+				"ab": "b",
+				"b":  "d",
+			}, gorax.WithAggregate(concat{}))
+
+			Ω(t.Aggregate("")).Should(Equal("abdc"))
+		})
+		It("should_aggregate_segments", func() {
+			t := gorax.FromMap(map[string]interface{}{
+				"/api/user":   1,
+				"/api/user/1": 2,
+				"/api/users":  4,
+			}, gorax.WithAggregate(sum{}), gorax.WithSeparator('/'))
+
+			Ω(t.Aggregate("/api/user")).Should(Equal(3))
+			Ω(t.Aggregate("/api/")).Should(Equal(7))
+		})
+		It("should_match_walk_prefix", func() {
+			t := gorax.New(gorax.WithAggregate(sum{}))
+
+			keys := []string{}
+			for i := 0; i < FuzzyTestSize; i++ {
+				key := randString(rand.Intn(16))
+				keys = append(keys, key)
+
+				switch rand.Intn(4) {
+				case 0:
+					t.Delete(keys[rand.Intn(len(keys))])
+				case 1:
+					key = keys[rand.Intn(len(keys))]
+					t.DeletePrefix(key[:rand.Intn(len(key)+1)])
+				default:
+					t.Insert(key, rand.Intn(100))
+				}
+
+				prefix := keys[rand.Intn(len(keys))]
+				prefix = prefix[:rand.Intn(len(prefix)+1)]
+
+				expected := 0
+				t.WalkPrefix(prefix, func(key string, value interface{}) bool {
+					Ω(strings.HasPrefix(key, prefix)).Should(BeTrue())
+					expected += value.(int)
+
+					return false
+				})
+
+				Ω(t.Aggregate(prefix)).Should(Equal(expected))
+			}
+		})
+	})
+})
@@ -0,0 +1,118 @@
+package gorax
+
+import (
+	"strings"
+	"unsafe"
+)
+
+const (
+	// slabSize is the number of nodes allocated at once by an arena.
+	slabSize = 256
+	// labelChunkSize is the minimal number of bytes allocated at once for the edge labels by an arena.
+	labelChunkSize = 4096
+)
+
+// arena allocates the nodes and edge labels of a Tree. If enabled by WithArena, nodes are allocated in slabs and
+// reused after DeletePrefix, Delete and Reset, and edge labels are copied into large byte chunks. Otherwise every node
+// and label is allocated on its own.
+type arena struct {
+	enabled bool
+
+	// slabs are all allocated slabs, nodes is the slab currently used and next the index of the slab used after it
+	slabs [][]node
+	nodes []node
+	next  int
+
+	// free are the released nodes which are reused before allocating new ones
+	free []*node
+
+	// labels is the chunk the edge labels are appended to, its bytes are never changed once used by a label
+	labels []byte
+}
+
+// newNode returns an empty node.
+func (a *arena) newNode() *node {
+	if !a.enabled {
+		return &node{}
+	}
+
+	if i := len(a.free) - 1; i >= 0 {
+		n := a.free[i]
+		a.free = a.free[:i]
+
+		return n
+	}
+
+	if len(a.nodes) == cap(a.nodes) {
+		if a.next == len(a.slabs) {
+			a.slabs = append(a.slabs, make([]node, 0, slabSize))
+		}
+		a.nodes = a.slabs[a.next][:0]
+		a.next += 1
+	}
+
+	a.nodes = a.nodes[:len(a.nodes)+1]
+
+	return &a.nodes[len(a.nodes)-1]
+}
+
+// release releases a node which is no longer part of the Tree to be reused.
+func (a *arena) release(n *node) {
+	if !a.enabled {
+		return
+	}
+
+	*n = node{}
+	a.free = append(a.free, n)
+}
+
+// releaseAll releases a node and its whole subtree.
+func (a *arena) releaseAll(n *node) {
+	if !a.enabled {
+		return
+	}
+
+	for _, child := range n.children {
+		a.releaseAll(child)
+	}
+	a.release(n)
+}
+
+// label returns the concatenation of the parts as edge label.
+func (a *arena) label(parts ...string) string {
+	if !a.enabled {
+		return strings.Join(parts, "")
+	}
+
+	var size int
+	for _, part := range parts {
+		size += len(part)
+	}
+	if size == 0 {
+		return ""
+	}
+
+	if cap(a.labels)-len(a.labels) < size {
+		a.labels = make([]byte, 0, max(labelChunkSize, size))
+	}
+
+	start := len(a.labels)
+	for _, part := range parts {
+		a.labels = append(a.labels, part...)
+	}
+
+	return unsafe.String(&a.labels[start], size)
+}
+
+// reset releases all nodes while keeping the slabs. Labels are not reused as they may still be referenced by keys
+// returned from the Tree.
+func (a *arena) reset() {
+	for _, slab := range a.slabs {
+		clear(slab[:cap(slab)])
+	}
+
+	a.nodes = nil
+	a.next = 0
+	a.free = a.free[:0]
+	a.labels = nil
+}
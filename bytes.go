@@ -0,0 +1,69 @@
+package gorax
+
+import "unsafe"
+
+// WalkBytesFn is used when walking the Tree with byte slice keys. The key is only valid until the function returns,
+// it must be copied to be retained. Returning 'true' terminates the iteration.
+type WalkBytesFn func(key []byte, value interface{}) bool
+
+// InsertBytes is like Insert, but takes the key as byte slice. The key is copied as it is stored in the Tree.
+func (t *Tree) InsertBytes(key []byte, value interface{}) bool {
+	return t.Insert(string(key), value)
+}
+
+// GetBytes is like Get, but takes the key as byte slice without copying it.
+func (t *Tree) GetBytes(key []byte) (interface{}, bool) {
+	// a key found by Get is already tracked by the bounds of the Tree, so it is not retained
+	return t.Get(unsafeString(key))
+}
+
+// DeleteBytes is like Delete, but takes the key as byte slice without copying it.
+func (t *Tree) DeleteBytes(key []byte) (interface{}, bool) {
+	return t.Delete(unsafeString(key))
+}
+
+// LongestPrefixBytes is like LongestPrefix, but takes the key as byte slice without copying it. The returned prefix
+// is a subslice of the key, unless the Tree has a normalizer.
+func (t *Tree) LongestPrefixBytes(key []byte) ([]byte, interface{}, bool) {
+	prefix, value, ok := t.LongestPrefix(unsafeString(key))
+	if !ok {
+		return nil, nil, false
+	}
+
+	if t.normalizer != nil {
+		// the original key of the prefix may differ from the key
+		return []byte(prefix), value, true
+	}
+
+	return key[:len(prefix)], value, true
+}
+
+// WalkBytes is like Walk, but passes the keys to the WalkBytesFn in a buffer which is reused for all keys, instead of
+// allocating a string per key.
+func (t *Tree) WalkBytes(fn WalkBytesFn) {
+	t.expire()
+
+	visit := originalKeys(fn)
+	walk(&t.root, make([]byte, 0, 64), func(key []byte, node *node) bool {
+		if node.isKey() {
+			return visit(key, node)
+		}
+
+		return false
+	})
+}
+
+// WalkPrefixBytes is like WalkPrefix, but takes the prefix as byte slice and passes the keys to the WalkBytesFn in a
+// buffer which is reused for all keys, instead of allocating a string per key. The keys are walked in lexicographical
+// order.
+func (t *Tree) WalkPrefixBytes(prefix []byte, fn WalkBytesFn) {
+	t.expire()
+
+	t.walkKeys(t.normalizeKey(unsafeString(prefix)), originalKeys(fn))
+}
+
+// unsafeString returns a string sharing the memory of a byte slice, which must not be modified while the string is
+// used.
+func unsafeString(b []byte) string {
+	return unsafe.String(unsafe.SliceData(b), len(b))
+}
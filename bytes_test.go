@@ -0,0 +1,101 @@
+package gorax_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/snorwin/gorax"
+)
+
+var _ = Describe("Tree", func() {
+	Context("Bytes", func() {
+		var (
+			t *gorax.Tree
+		)
+		BeforeEach(func() {
+			t = gorax.FromMap(map[string]interface{}{
+				"foo":    1,
+				"foobar": 2,
+				"foojin": 3,
+				"bar":    4,
+			})
+		})
+		It("should_insert_get_and_delete", func() {
+			key := []byte("barfoo")
+			Ω(t.InsertBytes(key, 5)).Should(BeTrue())
+			copy(key, "xxxxxx")
+
+			value, ok := t.GetBytes([]byte("barfoo"))
+			Ω(ok).Should(BeTrue())
+			Ω(value).Should(Equal(5))
+
+			value, ok = t.DeleteBytes([]byte("barfoo"))
+			Ω(ok).Should(BeTrue())
+			Ω(value).Should(Equal(5))
+
+			_, ok = t.GetBytes([]byte("barfoo"))
+			Ω(ok).Should(BeFalse())
+		})
+		It("should_find_longest_prefix", func() {
+			key, value, ok := t.LongestPrefixBytes([]byte("foobaz"))
+			Ω(ok).Should(BeTrue())
+			Ω(string(key)).Should(Equal("foo"))
+			Ω(value).Should(Equal(1))
+
+			_, _, ok = t.LongestPrefixBytes([]byte("jin"))
+			Ω(ok).Should(BeFalse())
+		})
+		It("should_walk_in_order", func() {
+			var keys []string
+			t.WalkBytes(func(key []byte, _ interface{}) bool {
+				keys = append(keys, string(key))
+
+				return false
+			})
+			Ω(keys).Should(Equal([]string{"bar", "foo", "foobar", "foojin"}))
+		})
+		It("should_walk_prefix_in_order", func() {
+			var keys []string
+			t.WalkPrefixBytes([]byte("fo"), func(key []byte, _ interface{}) bool {
+				keys = append(keys, string(key))
+
+				return false
+			})
+			Ω(keys).Should(Equal([]string{"foo", "foobar", "foojin"}))
+		})
+		It("should_walk_prefix_ending_in_compressed_node", func() {
+			var keys []string
+			t.WalkPrefixBytes([]byte("fooj"), func(key []byte, _ interface{}) bool {
+				keys = append(keys, string(key))
+
+				return false
+			})
+			Ω(keys).Should(Equal([]string{"foojin"}))
+		})
+		It("should_not_allocate_on_lookup", func() {
+			key := []byte("foobar")
+			Ω(testing.AllocsPerRun(100, func() {
+				t.GetBytes(key)
+				t.LongestPrefixBytes(key)
+			})).Should(BeZero())
+		})
+		It("should_not_allocate_per_walked_key", func() {
+			for i := 0; i < 1000; i++ {
+				t.Insert(randString(16), i)
+			}
+
+			Ω(testing.AllocsPerRun(10, func() {
+				t.WalkBytes(func(_ []byte, _ interface{}) bool {
+					return false
+				})
+			})).Should(BeNumerically("<", 16))
+			Ω(testing.AllocsPerRun(10, func() {
+				t.WalkPrefixBytes(nil, func(_ []byte, _ interface{}) bool {
+					return false
+				})
+			})).Should(BeNumerically("<", 16))
+		})
+	})
+})
@@ -0,0 +1,217 @@
+package gorax
+
+import (
+	"container/heap"
+	"container/list"
+	"strings"
+)
+
+// EvictionPolicy selects the keys evicted from a Tree with a capacity.
+type EvictionPolicy int
+
+const (
+	// LRU evicts the least recently used key.
+	LRU EvictionPolicy = iota
+	// LFU evicts the least frequently used key, and the least recently used among them.
+	LFU
+)
+
+// bound limits the number of keys under a prefix.
+type bound struct {
+	prefix   string
+	capacity int
+	usage    usage
+}
+
+// usage tracks the use of the keys under the prefix of a bound.
+type usage interface {
+	// touch records the use of a key, adding it if it is not tracked yet.
+	touch(key string)
+	// remove stops tracking a key.
+	remove(key string)
+	// victim returns the key to evict next, which must not be the excluded key.
+	victim(exclude string) (string, bool)
+	// len returns the number of tracked keys.
+	len() int
+}
+
+// touch records the use of a key by Insert, Get or LongestPrefix.
+func (t *Tree) touch(key string) {
+	for _, b := range t.bounds {
+		if strings.HasPrefix(key, b.prefix) {
+			if b.usage == nil {
+				b.usage = newUsage(t.policy)
+			}
+			b.usage.touch(key)
+		}
+	}
+}
+
+// untrack stops tracking a deleted key.
+func (t *Tree) untrack(key string) {
+	for _, b := range t.bounds {
+		if b.usage != nil && strings.HasPrefix(key, b.prefix) {
+			b.usage.remove(key)
+		}
+	}
+}
+
+// shrink evicts keys until all bounds of an inserted key are within their capacity, the inserted key itself is never
+// evicted.
+func (t *Tree) shrink(key string) {
+	for _, b := range t.bounds {
+		if !strings.HasPrefix(key, b.prefix) {
+			continue
+		}
+
+		for b.usage.len() > b.capacity {
+			victim, ok := b.usage.victim(key)
+			if !ok {
+				break
+			}
+
+			original, value, _ := t.remove(victim)
+			if t.evict != nil {
+				t.evict(original, value)
+			}
+		}
+	}
+}
+
+func newUsage(policy EvictionPolicy) usage {
+	if policy == LFU {
+		return &lfu{index: map[string]*lfuEntry{}}
+	}
+
+	return &lru{order: list.New(), elements: map[string]*list.Element{}}
+}
+
+// lru orders the keys by recency, the most recently used key is at the front.
+type lru struct {
+	order    *list.List
+	elements map[string]*list.Element
+}
+
+func (l *lru) touch(key string) {
+	if e, ok := l.elements[key]; ok {
+		l.order.MoveToFront(e)
+	} else {
+		l.elements[key] = l.order.PushFront(key)
+	}
+}
+
+func (l *lru) remove(key string) {
+	if e, ok := l.elements[key]; ok {
+		l.order.Remove(e)
+		delete(l.elements, key)
+	}
+}
+
+func (l *lru) victim(exclude string) (string, bool) {
+	for e := l.order.Back(); e != nil; e = e.Prev() {
+		if e.Value.(string) != exclude {
+			return e.Value.(string), true
+		}
+	}
+
+	return "", false
+}
+
+func (l *lru) len() int {
+	return len(l.elements)
+}
+
+// lfu is a min-heap of the keys ordered by frequency and recency.
+type lfu struct {
+	entries lfuEntries
+	index   map[string]*lfuEntry
+	tick    uint64
+}
+
+type lfuEntry struct {
+	key       string
+	frequency uint64
+	tick      uint64
+	index     int
+}
+
+func (l *lfu) touch(key string) {
+	l.tick += 1
+
+	if e, ok := l.index[key]; ok {
+		e.frequency += 1
+		e.tick = l.tick
+		heap.Fix(&l.entries, e.index)
+	} else {
+		e = &lfuEntry{key: key, frequency: 1, tick: l.tick}
+		l.index[key] = e
+		heap.Push(&l.entries, e)
+	}
+}
+
+func (l *lfu) remove(key string) {
+	if e, ok := l.index[key]; ok {
+		heap.Remove(&l.entries, e.index)
+		delete(l.index, key)
+	}
+}
+
+func (l *lfu) victim(exclude string) (string, bool) {
+	if len(l.entries) == 0 {
+		return "", false
+	}
+	if l.entries[0].key != exclude {
+		return l.entries[0].key, true
+	}
+
+	// the excluded key is the minimum, the next smallest is one of its children
+	var ret *lfuEntry
+	for i := 1; i <= 2 && i < len(l.entries); i++ {
+		if ret == nil || l.entries.Less(i, ret.index) {
+			ret = l.entries[i]
+		}
+	}
+	if ret == nil {
+		return "", false
+	}
+
+	return ret.key, true
+}
+
+func (l *lfu) len() int {
+	return len(l.index)
+}
+
+type lfuEntries []*lfuEntry
+
+func (e lfuEntries) Len() int {
+	return len(e)
+}
+
+func (e lfuEntries) Less(i, j int) bool {
+	if e[i].frequency != e[j].frequency {
+		return e[i].frequency < e[j].frequency
+	}
+
+	return e[i].tick < e[j].tick
+}
+
+func (e lfuEntries) Swap(i, j int) {
+	e[i], e[j] = e[j], e[i]
+	e[i].index = i
+	e[j].index = j
+}
+
+func (e *lfuEntries) Push(x interface{}) {
+	entry := x.(*lfuEntry)
+	entry.index = len(*e)
+	*e = append(*e, entry)
+}
+
+func (e *lfuEntries) Pop() interface{} {
+	old := *e
+	ret := old[len(old)-1]
+	*e = old[:len(old)-1]
+
+	return ret
+}
@@ -0,0 +1,108 @@
+package gorax_test
+
+import (
+	"fmt"
+	"math/rand"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/snorwin/gorax"
+)
+
+var _ = Describe("Tree", func() {
+	Context("WithCapacity", func() {
+		var (
+			evicted []string
+		)
+		BeforeEach(func() {
+			evicted = nil
+		})
+		onEvict := gorax.WithEvictFn(func(key string, _ interface{}) {
+			evicted = append(evicted, key)
+		})
+		It("should_evict_least_recently_used", func() {
+			t := gorax.New(gorax.WithCapacity(3), onEvict)
+			t.Insert("foo", 1)
+			t.Insert("bar", 2)
+			t.Insert("jin", 3)
+
+			_, _ = t.Get("foo")
+			_, _, _ = t.LongestPrefix("barbar")
+
+			t.Insert("foobar", 4)
+			t.Insert("barbar", 5)
+
+			Ω(evicted).Should(Equal([]string{"jin", "foo"}))
+			Ω(t.Len()).Should(Equal(3))
+			Ω(t.ToMap()).Should(Equal(map[string]interface{}{"bar": 2, "foobar": 4, "barbar": 5}))
+		})
+		It("should_evict_least_frequently_used", func() {
+			t := gorax.New(gorax.WithCapacity(3), gorax.WithEvictionPolicy(gorax.LFU), onEvict)
+			t.Insert("foo", 1)
+			t.Insert("bar", 2)
+			t.Insert("jin", 3)
+
+			_, _ = t.Get("foo")
+			_, _ = t.Get("foo")
+			_, _ = t.Get("jin")
+			_, _ = t.Get("bar")
+
+			t.Insert("foobar", 4)
+			t.Insert("barbar", 5)
+
+			Ω(evicted).Should(Equal([]string{"jin", "foobar"}))
+			Ω(t.ToMap()).Should(Equal(map[string]interface{}{"foo": 1, "bar": 2, "barbar": 5}))
+		})
+		It("should_not_track_deleted_keys", func() {
+			t := gorax.New(gorax.WithCapacity(2), onEvict)
+			t.Insert("foo", 1)
+			t.Insert("foobar", 2)
+			t.DeletePrefix("foo")
+			t.Insert("bar", 3)
+			t.Insert("jin", 4)
+
+			Ω(evicted).Should(BeEmpty())
+
+			t.Delete("bar")
+			t.Insert("foo", 5)
+
+			Ω(evicted).Should(BeEmpty())
+			Ω(t.Len()).Should(Equal(2))
+		})
+		It("should_limit_keys_under_prefix", func() {
+			t := gorax.New(
+				gorax.WithPrefixCapacity("tenant/a/", 2),
+				gorax.WithPrefixCapacity("tenant/b/", 1),
+				onEvict,
+			)
+			for i := 0; i < 3; i++ {
+				t.Insert(fmt.Sprintf("tenant/a/%d", i), i)
+				t.Insert(fmt.Sprintf("tenant/b/%d", i), i)
+				t.Insert(fmt.Sprintf("tenant/c/%d", i), i)
+			}
+
+			Ω(evicted).Should(Equal([]string{"tenant/b/0", "tenant/a/0", "tenant/b/1"}))
+			Ω(t.Len()).Should(Equal(6))
+		})
+		It("should_never_exceed_capacity", func() {
+			for _, policy := range []gorax.EvictionPolicy{gorax.LRU, gorax.LFU} {
+				t := gorax.New(gorax.WithCapacity(100), gorax.WithEvictionPolicy(policy))
+				for i := 0; i < FuzzyTestSize; i++ {
+					key := randString(rand.Intn(8))
+					switch rand.Intn(4) {
+					case 0:
+						t.Get(key)
+					case 1:
+						t.DeletePrefix(key)
+					default:
+						t.Insert(key, i)
+					}
+
+					Ω(t.Len()).Should(BeNumerically("<=", 100))
+					Ω(len(t.ToMap())).Should(Equal(t.Len()))
+				}
+			}
+		})
+	})
+})
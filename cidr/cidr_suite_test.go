@@ -0,0 +1,13 @@
+package cidr_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestCIDR(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "CIDR Test Suite")
+}
@@ -0,0 +1,65 @@
+package cidr
+
+import (
+	"net/netip"
+
+	"github.com/snorwin/gorax"
+)
+
+// node is a node of the bit-level radix tree. The edge to a child is compressed, the child's prefix may be any number
+// of bits longer than the prefix of its parent.
+type node struct {
+	prefix   netip.Prefix
+	children [2]*node
+	value    interface{}
+}
+
+func (n *node) isKey() bool {
+	return n.value != nil
+}
+
+func (n *node) getValue() interface{} {
+	if _, isNil := n.value.(gorax.Nil); isNil {
+		return nil
+	}
+
+	return n.value
+}
+
+// bit returns the i-th most significant bit of an address.
+func bit(addr netip.Addr, i int) int {
+	b := bytesOf(addr)
+
+	return int(b[i/8]>>(7-i%8)) & 1
+}
+
+// commonBits returns the length of the longest common prefix of two prefixes.
+func commonBits(a, b netip.Prefix) int {
+	n := min(a.Bits(), b.Bits())
+
+	x, y := bytesOf(a.Addr()), bytesOf(b.Addr())
+	for i := 0; i < n; i += 8 {
+		if diff := x[i/8] ^ y[i/8]; diff != 0 {
+			for j := i; j < i+8; j++ {
+				if diff&(0x80>>(j-i)) != 0 {
+					return min(j, n)
+				}
+			}
+		}
+	}
+
+	return n
+}
+
+// bytesOf returns the address bytes, IPv4 addresses are stored in the first 4 bytes.
+func bytesOf(addr netip.Addr) [16]byte {
+	if addr.Is4() {
+		var b [16]byte
+		a := addr.As4()
+		copy(b[:], a[:])
+
+		return b
+	}
+
+	return addr.As16()
+}
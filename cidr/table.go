@@ -0,0 +1,280 @@
+// Package cidr implements a bit-level radix tree for IP routing tables.
+//
+// Unlike the byte oriented gorax.Tree, prefixes may end in the middle of a byte (e.g. 10.0.16.0/20), which makes it
+// possible to do longest-prefix-match lookups of IPv4 and IPv6 addresses.
+package cidr
+
+import (
+	"net/netip"
+
+	"github.com/snorwin/gorax"
+)
+
+// Table implements a bit-level radix tree mapping IP prefixes to values.
+type Table struct {
+	v4   node
+	v6   node
+	size int
+}
+
+// New returns an empty Table.
+func New() *Table {
+	return &Table{
+		v4: node{prefix: netip.PrefixFrom(netip.IPv4Unspecified(), 0)},
+		v6: node{prefix: netip.PrefixFrom(netip.IPv6Unspecified(), 0)},
+	}
+}
+
+// Len returns the number of prefixes in the Table.
+func (t *Table) Len() int {
+	return t.size
+}
+
+// Insert adds a new prefix or updates an existing prefix. Returns 'true' if the prefix was added. The prefix is
+// masked before it is inserted and invalid prefixes are ignored.
+func (t *Table) Insert(prefix netip.Prefix, value interface{}) bool {
+	if !prefix.IsValid() {
+		return false
+	}
+	if value == nil {
+		value = gorax.Nil{}
+	}
+
+	prefix = prefix.Masked()
+
+	current := t.root(prefix.Addr())
+	for current.prefix != prefix {
+		b := bit(prefix.Addr(), current.prefix.Bits())
+
+		child := current.children[b]
+		if child == nil {
+			// append a new leaf
+			current.children[b] = &node{prefix: prefix, value: value}
+			t.size += 1
+			return true
+		}
+
+		if child.prefix.Bits() <= prefix.Bits() && child.prefix.Contains(prefix.Addr()) {
+			current = child
+			continue
+		}
+
+		// split the compressed edge to the child at the first differing bit
+		common := netip.PrefixFrom(prefix.Addr(), commonBits(child.prefix, prefix)).Masked()
+		split := &node{prefix: common}
+		split.children[bit(child.prefix.Addr(), common.Bits())] = child
+		if common == prefix {
+			split.value = value
+		} else {
+			split.children[bit(prefix.Addr(), common.Bits())] = &node{prefix: prefix, value: value}
+		}
+		current.children[b] = split
+
+		t.size += 1
+		return true
+	}
+
+	// insert or update value
+	added := !current.isKey()
+	current.value = value
+	if added {
+		t.size += 1
+	}
+	return added
+}
+
+// Get is used to lookup a specific prefix and returns the value and if it was found.
+func (t *Table) Get(prefix netip.Prefix) (interface{}, bool) {
+	if !prefix.IsValid() {
+		return nil, false
+	}
+
+	current, _ := t.find(prefix.Masked())
+	if current == nil || !current.isKey() {
+		return nil, false
+	}
+
+	return current.getValue(), true
+}
+
+// Lookup returns the most specific prefix containing the address, its value and if it was found.
+func (t *Table) Lookup(addr netip.Addr) (netip.Prefix, interface{}, bool) {
+	if !addr.IsValid() {
+		return netip.Prefix{}, nil, false
+	}
+
+	var match *node
+	t.walkPath(netip.PrefixFrom(addr, addr.BitLen()), func(n *node) bool {
+		if n.isKey() {
+			match = n
+		}
+
+		return false
+	})
+
+	if match == nil {
+		return netip.Prefix{}, nil, false
+	}
+
+	return match.prefix, match.getValue(), true
+}
+
+// Delete deletes a prefix and returns the previous value and if it was deleted.
+func (t *Table) Delete(prefix netip.Prefix) (interface{}, bool) {
+	if !prefix.IsValid() {
+		return nil, false
+	}
+
+	current, parent := t.find(prefix.Masked())
+	if current == nil || !current.isKey() {
+		return nil, false
+	}
+
+	value := current.getValue()
+	current.value = nil
+
+	t.size -= 1
+
+	// the roots are never removed
+	if parent == nil {
+		return value, true
+	}
+
+	// remove or splice out the node if it is not needed as branching node anymore
+	switch {
+	case current.children[0] == nil && current.children[1] == nil:
+		parent.children[bit(current.prefix.Addr(), parent.prefix.Bits())] = nil
+	case current.children[0] == nil:
+		parent.children[bit(current.prefix.Addr(), parent.prefix.Bits())] = current.children[1]
+	case current.children[1] == nil:
+		parent.children[bit(current.prefix.Addr(), parent.prefix.Bits())] = current.children[0]
+	default:
+		return value, true
+	}
+
+	// splice out the parent if it became a non-key node with a single child
+	if grandparent := t.parent(parent); grandparent != nil && !parent.isKey() {
+		if parent.children[0] == nil {
+			grandparent.children[bit(parent.prefix.Addr(), grandparent.prefix.Bits())] = parent.children[1]
+		} else if parent.children[1] == nil {
+			grandparent.children[bit(parent.prefix.Addr(), grandparent.prefix.Bits())] = parent.children[0]
+		}
+	}
+
+	return value, true
+}
+
+// WalkFn is used when walking the Table. Takes a prefix and value, returning 'true' if iteration should be terminated.
+type WalkFn func(prefix netip.Prefix, value interface{}) bool
+
+// Walk walks all prefixes of the Table, IPv4 before IPv6, ordered by address and prefix length.
+func (t *Table) Walk(fn WalkFn) {
+	if !walk(&t.v4, fn) {
+		walk(&t.v6, fn)
+	}
+}
+
+// Supernets walks all prefixes containing the given prefix, including the prefix itself, from the least to the most
+// specific one.
+func (t *Table) Supernets(prefix netip.Prefix, fn WalkFn) {
+	if !prefix.IsValid() {
+		return
+	}
+
+	t.walkPath(prefix.Masked(), func(n *node) bool {
+		if n.isKey() {
+			return fn(n.prefix, n.getValue())
+		}
+
+		return false
+	})
+}
+
+// Subnets walks all prefixes contained in the given prefix, including the prefix itself, ordered by address and
+// prefix length.
+func (t *Table) Subnets(prefix netip.Prefix, fn WalkFn) {
+	if !prefix.IsValid() {
+		return
+	}
+
+	prefix = prefix.Masked()
+
+	current := t.root(prefix.Addr())
+	for current != nil && current.prefix.Bits() < prefix.Bits() {
+		current = current.children[bit(prefix.Addr(), current.prefix.Bits())]
+	}
+
+	// the first node at least as long as the prefix contains all subnets if it is contained in the prefix
+	if current != nil && prefix.Contains(current.prefix.Addr()) {
+		walk(current, fn)
+	}
+}
+
+func (t *Table) root(addr netip.Addr) *node {
+	if addr.Is4() {
+		return &t.v4
+	}
+
+	return &t.v6
+}
+
+// find returns the node of a masked prefix and its parent, or nil if there is no such node.
+func (t *Table) find(prefix netip.Prefix) (*node, *node) {
+	var parent *node
+
+	current := t.root(prefix.Addr())
+	for current != nil && current.prefix.Bits() < prefix.Bits() {
+		parent = current
+		current = current.children[bit(prefix.Addr(), current.prefix.Bits())]
+	}
+
+	if current == nil || current.prefix != prefix {
+		return nil, nil
+	}
+
+	return current, parent
+}
+
+// parent returns the parent of a node or nil if the node is a root.
+func (t *Table) parent(n *node) *node {
+	_, parent := t.find(n.prefix)
+
+	return parent
+}
+
+// walkPath calls fn for every node from the root down to the most specific node containing the prefix.
+func (t *Table) walkPath(prefix netip.Prefix, fn func(*node) bool) {
+	current := t.root(prefix.Addr())
+	for current != nil && current.prefix.Bits() <= prefix.Bits() && current.prefix.Contains(prefix.Addr()) {
+		if fn(current) || current.prefix.Bits() == prefix.Bits() {
+			return
+		}
+
+		current = current.children[bit(prefix.Addr(), current.prefix.Bits())]
+	}
+}
+
+// walk walks the subtree in pre-order and returns 'true' if the iteration was terminated.
+func walk(start *node, fn WalkFn) bool {
+	nodes := []*node{start}
+
+	for len(nodes) > 0 {
+		// pop node
+		current := nodes[len(nodes)-1]
+		nodes = nodes[:len(nodes)-1]
+
+		// call function
+		if current.isKey() && fn(current.prefix, current.getValue()) {
+			return true
+		}
+
+		// push child nodes in reverse order, so that the 0 branch is visited first
+		for i := len(current.children) - 1; i >= 0; i-- {
+			if current.children[i] != nil {
+				nodes = append(nodes, current.children[i])
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,179 @@
+package cidr_test
+
+import (
+	"math/rand"
+	"net/netip"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/snorwin/gorax/cidr"
+)
+
+var _ = Describe("Table", func() {
+	var (
+		t *cidr.Table
+	)
+	BeforeEach(func() {
+		t = cidr.New()
+		for i, prefix := range []string{
+			"0.0.0.0/0",
+			"10.0.0.0/8",
+			"10.0.16.0/20",
+			"10.0.17.0/24",
+			"10.0.32.0/20",
+			"192.168.0.0/16",
+			"2001:db8::/32",
+			"2001:db8:1::/48",
+		} {
+			Ω(t.Insert(netip.MustParsePrefix(prefix), i)).Should(BeTrue())
+		}
+	})
+	Context("Insert", func() {
+		It("should_overwrite", func() {
+			Ω(t.Insert(netip.MustParsePrefix("10.0.16.0/20"), "new")).Should(BeFalse())
+			Ω(t.Len()).Should(Equal(8))
+
+			value, ok := t.Get(netip.MustParsePrefix("10.0.16.0/20"))
+			Ω(ok).Should(BeTrue())
+			Ω(value).Should(Equal("new"))
+		})
+		It("should_mask_prefix", func() {
+			Ω(t.Insert(netip.MustParsePrefix("172.16.1.1/12"), nil)).Should(BeTrue())
+
+			value, ok := t.Get(netip.MustParsePrefix("172.16.0.0/12"))
+			Ω(ok).Should(BeTrue())
+			Ω(value).Should(BeNil())
+		})
+		It("should_ignore_invalid_prefix", func() {
+			Ω(t.Insert(netip.Prefix{}, 1)).Should(BeFalse())
+			Ω(t.Len()).Should(Equal(8))
+		})
+	})
+	Context("Get", func() {
+		It("should_not_find_intermediate_prefix", func() {
+			_, ok := t.Get(netip.MustParsePrefix("10.0.0.0/16"))
+			Ω(ok).Should(BeFalse())
+		})
+	})
+	Context("Lookup", func() {
+		It("should_not_fail_if_empty", func() {
+			_, _, ok := cidr.New().Lookup(netip.MustParseAddr("10.0.0.1"))
+			Ω(ok).Should(BeFalse())
+		})
+		It("should_find_most_specific_route", func() {
+			for addr, expected := range map[string]string{
+				"10.0.17.1":     "10.0.17.0/24",
+				"10.0.18.1":     "10.0.16.0/20",
+				"10.0.47.255":   "10.0.32.0/20",
+				"10.0.48.0":     "10.0.0.0/8",
+				"192.168.1.1":   "192.168.0.0/16",
+				"8.8.8.8":       "0.0.0.0/0",
+				"2001:db8:1::1": "2001:db8:1::/48",
+				"2001:db8:2::1": "2001:db8::/32",
+			} {
+				prefix, _, ok := t.Lookup(netip.MustParseAddr(addr))
+				Ω(ok).Should(BeTrue())
+				Ω(prefix.String()).Should(Equal(expected))
+			}
+		})
+		It("should_not_find_route", func() {
+			_, _, ok := t.Lookup(netip.MustParseAddr("2001:db9::1"))
+			Ω(ok).Should(BeFalse())
+		})
+	})
+	Context("Delete", func() {
+		It("should_not_fail_if_empty", func() {
+			_, ok := cidr.New().Delete(netip.MustParsePrefix("10.0.0.0/8"))
+			Ω(ok).Should(BeFalse())
+		})
+		It("should_delete_and_fall_back_to_less_specific_route", func() {
+			value, ok := t.Delete(netip.MustParsePrefix("10.0.17.0/24"))
+			Ω(ok).Should(BeTrue())
+			Ω(value).Should(Equal(3))
+			Ω(t.Len()).Should(Equal(7))
+
+			prefix, _, ok := t.Lookup(netip.MustParseAddr("10.0.17.1"))
+			Ω(ok).Should(BeTrue())
+			Ω(prefix.String()).Should(Equal("10.0.16.0/20"))
+		})
+	})
+	Context("Supernets", func() {
+		It("should_walk_supernets", func() {
+			var actual []string
+			t.Supernets(netip.MustParsePrefix("10.0.17.0/24"), func(prefix netip.Prefix, _ interface{}) bool {
+				actual = append(actual, prefix.String())
+
+				return false
+			})
+
+			Ω(actual).Should(Equal([]string{"0.0.0.0/0", "10.0.0.0/8", "10.0.16.0/20", "10.0.17.0/24"}))
+		})
+	})
+	Context("Subnets", func() {
+		It("should_walk_subnets", func() {
+			var actual []string
+			t.Subnets(netip.MustParsePrefix("10.0.0.0/18"), func(prefix netip.Prefix, _ interface{}) bool {
+				actual = append(actual, prefix.String())
+
+				return false
+			})
+
+			Ω(actual).Should(Equal([]string{"10.0.16.0/20", "10.0.17.0/24", "10.0.32.0/20"}))
+		})
+		It("should_walk_ipv6_subnets", func() {
+			var actual []string
+			t.Subnets(netip.MustParsePrefix("::/0"), func(prefix netip.Prefix, _ interface{}) bool {
+				actual = append(actual, prefix.String())
+
+				return false
+			})
+
+			Ω(actual).Should(Equal([]string{"2001:db8::/32", "2001:db8:1::/48"}))
+		})
+	})
+	Context("Fuzzy", func() {
+		It("should_match_linear_search", func() {
+			t = cidr.New()
+
+			m := map[netip.Prefix]int{}
+			for i := 0; i < 1000; i++ {
+				var b [4]byte
+				rand.Read(b[:])
+				prefix := netip.PrefixFrom(netip.AddrFrom4(b), rand.Intn(33)).Masked()
+
+				m[prefix] = i
+				t.Insert(prefix, i)
+
+				if i%3 == 0 {
+					for p := range m {
+						delete(m, p)
+						t.Delete(p)
+						break
+					}
+				}
+			}
+			Ω(t.Len()).Should(Equal(len(m)))
+
+			for i := 0; i < 1000; i++ {
+				var b [4]byte
+				rand.Read(b[:])
+				addr := netip.AddrFrom4(b)
+
+				var expected netip.Prefix
+				for p := range m {
+					if p.Contains(addr) && (!expected.IsValid() || p.Bits() > expected.Bits()) {
+						expected = p
+					}
+				}
+
+				prefix, value, ok := t.Lookup(addr)
+				Ω(ok).Should(Equal(expected.IsValid()))
+				if ok {
+					Ω(prefix).Should(Equal(expected))
+					Ω(value).Should(Equal(m[expected]))
+				}
+			}
+		})
+	})
+})
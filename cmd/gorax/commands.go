@@ -0,0 +1,267 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/snorwin/gorax"
+)
+
+type command func(args []string, stdin io.Reader, stdout, stderr io.Writer) error
+
+var commands = map[string]command{
+	"load":           loadCommand,
+	"get":            getCommand,
+	"prefix":         prefixCommand,
+	"longest-prefix": longestPrefixCommand,
+	"range":          rangeCommand,
+	"stats":          statsCommand,
+	"dot":            dotCommand,
+}
+
+func loadCommand(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	t, _, err := parse("load", "", 0, args, stdin, stderr)
+	if err != nil {
+		return err
+	}
+
+	printEntries(stdout, t.ToMap())
+
+	return nil
+}
+
+func getCommand(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	t, params, err := parse("get", "KEY", 1, args, stdin, stderr)
+	if err != nil {
+		return err
+	}
+
+	value, ok := t.Get(params[0])
+	if !ok {
+		return fmt.Errorf("key %q not found", params[0])
+	}
+	printEntry(stdout, params[0], value)
+
+	return nil
+}
+
+func prefixCommand(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	t, params, err := parse("prefix", "PREFIX", 1, args, stdin, stderr)
+	if err != nil {
+		return err
+	}
+
+	entries := map[string]interface{}{}
+	t.WalkPrefix(params[0], func(key string, value interface{}) bool {
+		entries[key] = value
+
+		return false
+	})
+	printEntries(stdout, entries)
+
+	return nil
+}
+
+func longestPrefixCommand(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	t, params, err := parse("longest-prefix", "KEY", 1, args, stdin, stderr)
+	if err != nil {
+		return err
+	}
+
+	key, value, ok := t.LongestPrefix(params[0])
+	if !ok {
+		return fmt.Errorf("no prefix of %q found", params[0])
+	}
+	printEntry(stdout, key, value)
+
+	return nil
+}
+
+func rangeCommand(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	t, params, err := parse("range", "FROM TO", 2, args, stdin, stderr)
+	if err != nil {
+		return err
+	}
+
+	entries := map[string]interface{}{}
+	t.Walk(func(key string, value interface{}) bool {
+		if params[0] <= key && key < params[1] {
+			entries[key] = value
+		}
+
+		return false
+	})
+	printEntries(stdout, entries)
+
+	return nil
+}
+
+func statsCommand(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	t, _, err := parse("stats", "", 0, args, stdin, stderr)
+	if err != nil {
+		return err
+	}
+
+	stats := t.Stats()
+
+	fanOut := make([]int, 0, len(stats.FanOut))
+	for children := range stats.FanOut {
+		fanOut = append(fanOut, children)
+	}
+	sort.Ints(fanOut)
+
+	fmt.Fprintf(stdout, "keys\t%d\n", stats.Keys)
+	fmt.Fprintf(stdout, "nodes\t%d\n", stats.Nodes)
+	fmt.Fprintf(stdout, "compressed nodes\t%d\n", stats.CompressedNodes)
+	fmt.Fprintf(stdout, "branching nodes\t%d\n", stats.BranchingNodes)
+	fmt.Fprintf(stdout, "leaf nodes\t%d\n", stats.LeafNodes)
+	fmt.Fprintf(stdout, "max depth\t%d\n", stats.MaxDepth)
+	fmt.Fprintf(stdout, "avg depth\t%.2f\n", stats.AverageDepth)
+	fmt.Fprintf(stdout, "edge bytes\t%d\n", stats.EdgeBytes)
+	fmt.Fprintf(stdout, "heap bytes\t%d\n", stats.HeapBytes)
+	for _, children := range fanOut {
+		fmt.Fprintf(stdout, "fan-out %d\t%d\n", children, stats.FanOut[children])
+	}
+
+	return nil
+}
+
+func dotCommand(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	fs, paths := newFlagSet("dot", "", stderr)
+	output := fs.String("o", "", "write the graph to `file` instead of stdout")
+	format := fs.String("format", "auto", "output format: dot, svg or auto (by the extension of the -o file, otherwise svg if Graphviz 'dot' is on PATH)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 0 {
+		fs.Usage()
+		return errors.New("unexpected arguments")
+	}
+
+	t, err := load(*paths, stdin)
+	if err != nil {
+		return err
+	}
+
+	graph := []byte(t.ToDOTGraph().String())
+
+	if *format == "auto" {
+		*format = autoFormat(*output)
+	}
+
+	switch *format {
+	case "svg":
+		graph, err = render(graph)
+		if err != nil {
+			return err
+		}
+	case "dot":
+	default:
+		return fmt.Errorf("unknown format %q", *format)
+	}
+
+	if *output == "" {
+		_, err = stdout.Write(graph)
+		return err
+	}
+
+	return os.WriteFile(*output, graph, 0o644)
+}
+
+// autoFormat returns the format implied by the extension of the output file, otherwise svg if Graphviz is installed.
+func autoFormat(output string) string {
+	switch strings.ToLower(filepath.Ext(output)) {
+	case ".svg":
+		return "svg"
+	case ".dot", ".gv":
+		return "dot"
+	}
+
+	if _, err := exec.LookPath("dot"); err != nil {
+		return "dot"
+	}
+
+	return "svg"
+}
+
+// render converts a DOT graph to SVG using the Graphviz 'dot' command.
+func render(graph []byte) ([]byte, error) {
+	path, err := exec.LookPath("dot")
+	if err != nil {
+		return nil, fmt.Errorf("svg output requires Graphviz: %w", err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(path, "-Tsvg")
+	cmd.Stdin = bytes.NewReader(graph)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("dot: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	return stdout.Bytes(), nil
+}
+
+// parse parses the flags of a command, checks the number of positional parameters and loads the input.
+func parse(name, usage string, n int, args []string, stdin io.Reader, stderr io.Writer) (*gorax.Tree, []string, error) {
+	fs, paths := newFlagSet(name, usage, stderr)
+	if err := fs.Parse(args); err != nil {
+		return nil, nil, err
+	}
+	if fs.NArg() != n {
+		fs.Usage()
+		return nil, nil, fmt.Errorf("%s expects %d argument(s)", name, n)
+	}
+
+	t, err := load(*paths, stdin)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return t, fs.Args(), nil
+}
+
+func newFlagSet(name, usage string, stderr io.Writer) (*flag.FlagSet, *files) {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "usage: gorax %s [flags] %s\n", name, usage)
+		fs.PrintDefaults()
+	}
+
+	paths := &files{}
+	fs.Var(paths, "f", "read entries from `file` (repeatable, default stdin)")
+
+	return fs, paths
+}
+
+// printEntries prints entries sorted by key.
+func printEntries(w io.Writer, entries map[string]interface{}) {
+	keys := make([]string, 0, len(entries))
+	for key := range entries {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		printEntry(w, key, entries[key])
+	}
+}
+
+// printEntry prints a key and its value separated by a tab, or only the key if it has no value.
+func printEntry(w io.Writer, key string, value interface{}) {
+	if value == nil {
+		fmt.Fprintln(w, key)
+	} else {
+		fmt.Fprintf(w, "%s\t%v\n", key, value)
+	}
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+const input = "foo\t1\nfoobar\t2\nbar\nfoojin\t3\nbaz\t4\n"
+
+var _ = Describe("Command", func() {
+	var (
+		stdout *bytes.Buffer
+		stderr *bytes.Buffer
+		dir    string
+	)
+	BeforeEach(func() {
+		stdout = &bytes.Buffer{}
+		stderr = &bytes.Buffer{}
+
+		// dir of ginkgo v1 returns an empty path, i.e. the package directory
+		var err error
+		dir, err = os.MkdirTemp("", "gorax")
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+	AfterEach(func() {
+		Ω(os.RemoveAll(dir)).Should(Succeed())
+	})
+	execute := func(args ...string) error {
+		return run(args, strings.NewReader(input), stdout, stderr)
+	}
+	Context("run", func() {
+		It("should_fail_without_command", func() {
+			Ω(execute()).ShouldNot(Succeed())
+		})
+		It("should_fail_with_unknown_command", func() {
+			Ω(execute("foo")).ShouldNot(Succeed())
+		})
+		It("should_fail_with_wrong_number_of_arguments", func() {
+			Ω(execute("get")).ShouldNot(Succeed())
+		})
+	})
+	Context("load", func() {
+		It("should_print_sorted_entries", func() {
+			Ω(execute("load")).Should(Succeed())
+			Ω(stdout.String()).Should(Equal("bar\nbaz\t4\nfoo\t1\nfoobar\t2\nfoojin\t3\n"))
+		})
+		It("should_read_files", func() {
+			first := filepath.Join(dir, "first.txt")
+			second := filepath.Join(dir, "second.tsv")
+			Ω(os.WriteFile(first, []byte("foo\nbar\r\n\n"), 0o644)).Should(Succeed())
+			Ω(os.WriteFile(second, []byte("foo\t1\n"), 0o644)).Should(Succeed())
+
+			Ω(execute("load", "-f", first, "-f", second)).Should(Succeed())
+			Ω(stdout.String()).Should(Equal("bar\nfoo\t1\n"))
+		})
+		It("should_fail_if_file_does_not_exist", func() {
+			Ω(execute("load", "-f", filepath.Join(dir, "missing"))).ShouldNot(Succeed())
+		})
+	})
+	Context("get", func() {
+		It("should_print_entry", func() {
+			Ω(execute("get", "foobar")).Should(Succeed())
+			Ω(stdout.String()).Should(Equal("foobar\t2\n"))
+		})
+		It("should_fail_if_not_found", func() {
+			Ω(execute("get", "fo")).ShouldNot(Succeed())
+		})
+	})
+	Context("prefix", func() {
+		It("should_print_entries_under_prefix", func() {
+			Ω(execute("prefix", "foo")).Should(Succeed())
+			Ω(stdout.String()).Should(Equal("foo\t1\nfoobar\t2\nfoojin\t3\n"))
+		})
+	})
+	Context("longest-prefix", func() {
+		It("should_print_longest_prefix", func() {
+			Ω(execute("longest-prefix", "foobarbaz")).Should(Succeed())
+			Ω(stdout.String()).Should(Equal("foobar\t2\n"))
+		})
+		It("should_fail_if_not_found", func() {
+			Ω(execute("longest-prefix", "jin")).ShouldNot(Succeed())
+		})
+	})
+	Context("range", func() {
+		It("should_print_entries_in_range", func() {
+			Ω(execute("range", "baz", "foobar")).Should(Succeed())
+			Ω(stdout.String()).Should(Equal("baz\t4\nfoo\t1\n"))
+		})
+	})
+	Context("stats", func() {
+		It("should_print_statistics", func() {
+			Ω(execute("stats")).Should(Succeed())
+			Ω(stdout.String()).Should(ContainSubstring("keys\t5\n"))
+			Ω(stdout.String()).Should(ContainSubstring("fan-out 2\t"))
+		})
+	})
+	Context("dot", func() {
+		It("should_write_dot_graph", func() {
+			Ω(execute("dot", "-format", "dot")).Should(Succeed())
+			Ω(stdout.String()).Should(HavePrefix("digraph"))
+		})
+		It("should_write_dot_graph_to_file", func() {
+			output := filepath.Join(dir, "tree.dot")
+
+			Ω(execute("dot", "-format", "dot", "-o", output)).Should(Succeed())
+			Ω(os.ReadFile(output)).Should(HavePrefix("digraph"))
+		})
+		It("should_select_format_by_extension", func() {
+
+			// without Graphviz the svg implied by the extension fails instead of writing DOT
+			path := os.Getenv("PATH")
+			defer os.Setenv("PATH", path)
+			Ω(os.Setenv("PATH", dir)).Should(Succeed())
+
+			Ω(execute("dot", "-o", filepath.Join(dir, "tree.svg"))).ShouldNot(Succeed())
+			Ω(filepath.Join(dir, "tree.svg")).ShouldNot(BeAnExistingFile())
+
+			Ω(execute("dot", "-o", filepath.Join(dir, "tree.gv"))).Should(Succeed())
+			Ω(os.ReadFile(filepath.Join(dir, "tree.gv"))).Should(HavePrefix("digraph"))
+		})
+		It("should_fail_with_unknown_format", func() {
+			Ω(execute("dot", "-format", "png")).ShouldNot(Succeed())
+		})
+	})
+})
@@ -0,0 +1,13 @@
+package main
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestGoraxCommand(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Gorax Command Test Suite")
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/snorwin/gorax"
+)
+
+// files is a flag.Value collecting the input files given with '-f'.
+type files []string
+
+func (f *files) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *files) Set(value string) error {
+	*f = append(*f, value)
+	return nil
+}
+
+// load reads all input files (or stdin if none are given) into a new Tree.
+func load(paths files, stdin io.Reader) (*gorax.Tree, error) {
+	t := gorax.New()
+
+	if len(paths) == 0 {
+		return t, read(t, stdin)
+	}
+
+	for _, path := range paths {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+
+		err = read(t, f)
+		_ = f.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return t, nil
+}
+
+// read inserts every line of r into the Tree. Lines containing a tab are split into key and value.
+func read(t *gorax.Tree, r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+		if line == "" {
+			continue
+		}
+
+		if key, value, ok := strings.Cut(line, "\t"); ok {
+			t.Insert(key, value)
+		} else {
+			t.Insert(line, nil)
+		}
+	}
+
+	return scanner.Err()
+}
@@ -0,0 +1,48 @@
+// Command gorax loads keys into a gorax radix tree and inspects them.
+//
+// Usage:
+//
+//	gorax <command> [flags] [args]
+//
+// The commands are:
+//
+//	load            print the loaded entries sorted as TSV
+//	get KEY         print the value of a key
+//	prefix PREFIX   print all entries under a prefix
+//	longest-prefix KEY
+//	                print the entry with the longest prefix of a key
+//	range FROM TO   print all entries with FROM <= key < TO
+//	stats           print statistics about the tree
+//	dot             render the tree as DOT graph or SVG
+//
+// Every command reads its entries from the files given with -f (or stdin if
+// none are given). Lines containing a tab are read as 'key<TAB>value', all
+// other lines as keys without value.
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr); err != nil {
+		fmt.Fprintln(os.Stderr, "gorax:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	if len(args) == 0 {
+		return errors.New("missing command (load, get, prefix, longest-prefix, range, stats, dot)")
+	}
+
+	cmd, ok := commands[args[0]]
+	if !ok {
+		return fmt.Errorf("unknown command %q", args[0])
+	}
+
+	return cmd(args[1:], stdin, stdout, stderr)
+}
@@ -2,51 +2,94 @@ package gorax
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/emicklei/dot"
 )
 
 // ToDOTGraph walks the Tree  and converts it into a dot.Graph
 func (t *Tree) ToDOTGraph() *dot.Graph {
+	return t.ToDOTGraphWithOptions(GraphOptions{})
+}
+
+// ToDOTGraphWithOptions is like ToDOTGraph, but renders only the part of the Tree selected by the options. Collapsed
+// subtrees are rendered as dashed nodes labeled with the number of their keys, highlighted nodes and edges are red.
+func (t *Tree) ToDOTGraphWithOptions(opts GraphOptions) *dot.Graph {
 	// create new dot graph
 	graph := dot.NewGraph(dot.Directed)
 
-	walk(&t.root, func(key string, node *node) bool {
-		n := graph.Node(key)
-		if node.isKey() {
-			// set value in label
-			n.Attr("label", fmt.Sprintf("%s|%v", key, node.getValue()))
+	t.graph(opts, func(v graphNode, edges []graphEdge) {
+		n := graph.Node(v.ID)
+		if v.Collapsed > 0 {
+			n.Attr("label", fmt.Sprintf("+%d more", v.Collapsed))
+			n.Attr("style", "dashed")
+			return
+		}
+
+		if v.Key {
+			// set value in label, escaping the characters of the record syntax
+			n.Attr("label", dot.Literal(`"`+escapeRecord(v.ID)+"|"+escapeRecord(v.Value)+`"`))
 
 			// change shape
 			n.Attr("shape", "record")
 		}
 
-		if node.isLeaf() {
+		if v.Leaf {
 			// leaf nodes are blue
 			n.Attr("color", "blue")
 		}
 
-		if node.isCompressed() {
+		if v.Compressed {
 			// compressed nodes are green
 			n.Attr("color", "green")
+		}
+
+		if v.Highlighted {
+			n.Attr("color", "red")
+		}
 
-			// add compressed edge
-			n.Edge(graph.Node(key+node.key)).
-				Label(node.key).
-				Attr("color", "green")
-		} else {
-			// add all other edges
-			for i := 0; i < len(node.key); i++ {
-				n.Edge(graph.Node(key + string(node.key[i]))).
-					Label(string(node.key[i]))
+		for _, edge := range edges {
+			e := n.Edge(graph.Node(edge.To))
+			switch {
+			case edge.Collapsed:
+				e.Attr("style", "dashed")
+			case edge.Compressed:
+				// add compressed edge
+				e.Label(edge.Label).Attr("color", "green")
+			default:
+				// add all other edges
+				e.Label(edge.Label)
+			}
+
+			if edge.Highlighted {
+				e.Attr("color", "red")
 			}
 		}
 
-		return false
+		if v.Root && opts.Prefix == "" {
+			// create root
+			n.Attr("shape", "point")
+		}
 	})
 
-	// create root
-	graph.Node("").Attr("shape", "point")
-
 	return graph
 }
+
+// escapeRecord escapes a string for a double-quoted label of a record, i.e. the quotes and backslashes of the DOT
+// language, newlines and the special characters of the record syntax.
+func escapeRecord(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '"', '\\', '|', '{', '}', '<', '>':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case '\n':
+			b.WriteString(`\n`)
+		default:
+			b.WriteByte(c)
+		}
+	}
+
+	return b.String()
+}
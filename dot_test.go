@@ -2,6 +2,7 @@ package gorax_test
 
 import (
 	_ "embed"
+	"fmt"
 	"strings"
 
 	. "github.com/onsi/ginkgo"
@@ -36,4 +37,51 @@ var _ = Describe("Tree", func() {
 			Ω(strings.ReplaceAll(t.ToDOTGraph().String(), "\t\n", "\n")).Should(Equal(example))
 		})
 	})
+	Context("ToDOTGraphWithOptions", func() {
+		var (
+			t *gorax.Tree
+		)
+		BeforeEach(func() {
+			t = gorax.FromMap(map[string]interface{}{
+				"foo":    1,
+				"foobar": 2,
+				"fox":    3,
+				"bar":    nil,
+			})
+		})
+		It("should_render_subtree", func() {
+			graph := t.ToDOTGraphWithOptions(gorax.GraphOptions{Prefix: "fo"}).String()
+			Ω(graph).Should(ContainSubstring(`n1[label="fo"];`))
+			Ω(graph).Should(ContainSubstring(`label="foobar|2"`))
+			Ω(graph).ShouldNot(ContainSubstring(`"bar|`))
+			Ω(graph).ShouldNot(ContainSubstring(`shape="point"`))
+
+			Ω(t.ToDOTGraphWithOptions(gorax.GraphOptions{Prefix: "jin"}).String()).ShouldNot(ContainSubstring("label"))
+		})
+		It("should_collapse_subtrees_below_max_depth", func() {
+			graph := t.ToDOTGraphWithOptions(gorax.GraphOptions{MaxDepth: 2}).String()
+			Ω(graph).Should(ContainSubstring(`[label="+3 more",style="dashed"];`))
+			Ω(graph).Should(ContainSubstring(`label="bar|\<nil\>"`))
+			Ω(graph).ShouldNot(ContainSubstring(`foo|`))
+		})
+		It("should_highlight_lookup_path", func() {
+			graph := t.ToDOTGraphWithOptions(gorax.GraphOptions{Highlight: "foobaz"}).String()
+			Ω(strings.Count(graph, `color="red"`)).Should(Equal(7))
+			Ω(graph).Should(ContainSubstring(`[color="red",label="foo|1",shape="record"];`))
+			Ω(graph).Should(ContainSubstring(`[color="blue",label="foobar|2",shape="record"];`))
+		})
+		It("should_format_values", func() {
+			graph := t.ToDOTGraphWithOptions(gorax.GraphOptions{
+				FormatValue: func(value interface{}) string {
+					return fmt.Sprintf("%03d", value)
+				},
+			}).String()
+			Ω(graph).Should(ContainSubstring(`label="foobar|002"`))
+		})
+		It("should_escape_record_labels", func() {
+			t := gorax.New()
+			t.Insert(`a|{"b"}`, "<c>\\\n")
+			Ω(t.ToDOTGraph().String()).Should(ContainSubstring(`label="a\|\{\"b\"\}|\<c\>\\\n",shape="record"`))
+		})
+	})
 })
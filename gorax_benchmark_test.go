@@ -135,3 +135,148 @@ func benchmarkDelete(b *testing.B, size int) {
 		b.StopTimer()
 	}
 }
+
+func BenchmarkInsertFanOut16(b *testing.B) {
+	benchmarkInsertFanOut(b, 16)
+}
+
+func BenchmarkInsertFanOut48(b *testing.B) {
+	benchmarkInsertFanOut(b, 48)
+}
+
+func BenchmarkInsertFanOut128(b *testing.B) {
+	benchmarkInsertFanOut(b, 128)
+}
+
+func BenchmarkInsertFanOut256(b *testing.B) {
+	benchmarkInsertFanOut(b, 256)
+}
+
+func BenchmarkGetFanOut16(b *testing.B) {
+	benchmarkGetFanOut(b, 16)
+}
+
+func BenchmarkGetFanOut48(b *testing.B) {
+	benchmarkGetFanOut(b, 48)
+}
+
+func BenchmarkGetFanOut128(b *testing.B) {
+	benchmarkGetFanOut(b, 128)
+}
+
+func BenchmarkGetFanOut256(b *testing.B) {
+	benchmarkGetFanOut(b, 256)
+}
+
+func benchmarkInsertFanOut(b *testing.B, fanOut int) {
+	keys := fanOutKeys(fanOut)
+
+	b.StopTimer()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		t := gorax.New()
+
+		b.StartTimer()
+		for j := 0; j < len(keys); j++ {
+			t.Insert(keys[j], "")
+		}
+		b.StopTimer()
+	}
+}
+
+func benchmarkGetFanOut(b *testing.B, fanOut int) {
+	keys := fanOutKeys(fanOut)
+
+	t := gorax.New()
+	for j := 0; j < len(keys); j++ {
+		t.Insert(keys[j], "")
+	}
+
+	b.StopTimer()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		b.StartTimer()
+		for j := 0; j < len(keys); j++ {
+			t.Get(keys[j])
+		}
+		b.StopTimer()
+	}
+}
+
+// fanOutKeys returns 10000 random keys of 8 bytes, each byte taken from an alphabet of the given size.
+func fanOutKeys(fanOut int) []string {
+	keys := make([]string, 10000)
+	for i := range keys {
+		key := make([]byte, 8)
+		for j := range key {
+			key[j] = byte(rand.Intn(fanOut))
+		}
+		keys[i] = string(key)
+	}
+
+	return keys
+}
+
+func BenchmarkInsertAllocs(b *testing.B) {
+	benchmarkInsertAllocs(b)
+}
+
+func BenchmarkInsertAllocsArena(b *testing.B) {
+	benchmarkInsertAllocs(b, gorax.WithArena())
+}
+
+// benchmarkInsertAllocs reports the allocations of inserting 10000 keys into a reset Tree.
+func benchmarkInsertAllocs(b *testing.B, opts ...gorax.Option) {
+	keys := make([]string, 10000)
+	for i := range keys {
+		keys[i] = randString(rand.Intn(BenchmarkMaxKeySize))
+	}
+
+	t := gorax.New(opts...)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		t.Reset()
+		for j := 0; j < len(keys); j++ {
+			t.Insert(keys[j], "")
+		}
+	}
+}
+
+func BenchmarkWalk10000(b *testing.B) {
+	t := benchmarkTree(10000)
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		t.Walk(func(_ string, _ interface{}) bool {
+			return false
+		})
+	}
+}
+
+func BenchmarkWalkBytes10000(b *testing.B) {
+	t := benchmarkTree(10000)
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		t.WalkBytes(func(_ []byte, _ interface{}) bool {
+			return false
+		})
+	}
+}
+
+// benchmarkTree returns a Tree with random keys.
+func benchmarkTree(size int) *gorax.Tree {
+	t := gorax.New()
+	for j := 0; j < size; j++ {
+		t.Insert(randString(rand.Intn(BenchmarkMaxKeySize)), "")
+	}
+
+	return t
+}
@@ -31,6 +31,7 @@ var _ = Describe("Fuzzy Tests", func() {
 
 				actual := !t.Insert(key, value)
 				Ω(actual).Should(Equal(expected))
+				Ω(t.Validate()).Should(Succeed())
 
 				Ω(t.ToMap()).Should(Equal(m))
 				Ω(t.Len()).Should(Equal(len(m)))
@@ -53,6 +54,7 @@ var _ = Describe("Fuzzy Tests", func() {
 
 				actual, ok := t.Delete(key)
 				Ω(ok).Should(BeTrue())
+				Ω(t.Validate()).Should(Succeed())
 
 				if expected == nil {
 					Ω(actual).Should(BeNil())
@@ -65,6 +67,87 @@ var _ = Describe("Fuzzy Tests", func() {
 			}
 		})
 	})
+	Context("Fan-out", func() {
+		It("should_grow_and_shrink_branching_nodes", func() {
+			t := gorax.New()
+
+			m := map[string]interface{}{}
+			for i := 0; i < FuzzyTestSize; i++ {
+				key := string([]byte{byte(rand.Intn(256)), byte(rand.Intn(256))})
+				if rand.Intn(3) == 0 {
+					_, expected := m[key]
+					delete(m, key)
+
+					_, ok := t.Delete(key)
+					Ω(ok).Should(Equal(expected))
+				} else {
+					m[key] = i
+					t.Insert(key, i)
+				}
+
+				Ω(t.Validate()).Should(Succeed())
+				Ω(t.Len()).Should(Equal(len(m)))
+			}
+
+			Ω(t.ToMap()).Should(Equal(m))
+			for key, expected := range m {
+				actual, ok := t.Get(key)
+				Ω(ok).Should(BeTrue())
+				Ω(actual).Should(Equal(expected))
+			}
+
+			for key := range m {
+				delete(m, key)
+				t.Delete(key)
+				Ω(t.Validate()).Should(Succeed())
+
+				for k := range m {
+					_, ok := t.Get(k)
+					Ω(ok).Should(BeTrue())
+					break
+				}
+			}
+			Ω(t.Len()).Should(BeZero())
+		})
+	})
+	Context("Arena", func() {
+		It("should_reuse_nodes", func() {
+			t := gorax.New(gorax.WithArena())
+
+			m := map[string]interface{}{}
+			for i := 0; i < FuzzyTestSize; i++ {
+				key := strings.Map(func(r rune) rune {
+					return rune("abc"[rand.Intn(3)])
+				}, randString(rand.Intn(16)))
+
+				switch rand.Intn(10) {
+				case 0:
+					prefix := key[:len(key)/2]
+					for k := range m {
+						if strings.HasPrefix(k, prefix) {
+							delete(m, k)
+						}
+					}
+					t.DeletePrefix(prefix)
+				case 1, 2, 3:
+					delete(m, key)
+					t.Delete(key)
+				default:
+					m[key] = i
+					t.Insert(key, i)
+				}
+
+				if i%(FuzzyTestSize/4) == 0 {
+					m = map[string]interface{}{}
+					t.Reset()
+				}
+
+				Ω(t.Validate()).Should(Succeed())
+				Ω(t.ToMap()).Should(Equal(m))
+				Ω(t.Len()).Should(Equal(len(m)))
+			}
+		})
+	})
 	Context("Minimum/Maximum", func() {
 		var (
 			t *gorax.Tree
@@ -102,6 +185,7 @@ var _ = Describe("Fuzzy Tests", func() {
 				}
 
 				t.Delete(key)
+				Ω(t.Validate()).Should(Succeed())
 			}
 		})
 		It("should_find_maximum", func() {
@@ -119,6 +203,7 @@ var _ = Describe("Fuzzy Tests", func() {
 				}
 
 				t.Delete(key)
+				Ω(t.Validate()).Should(Succeed())
 			}
 		})
 	})
@@ -0,0 +1,171 @@
+package gorax
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// GraphOptions are the parameters of the graph export of a Tree by ToDOTGraphWithOptions.
+type GraphOptions struct {
+	// Prefix limits the graph to the subtree under the prefix.
+	Prefix string
+
+	// MaxDepth is the maximum number of edges below the root of the graph, deeper subtrees are collapsed into a single
+	// node with the number of their keys. Zero means unlimited.
+	MaxDepth int
+
+	// Highlight highlights the nodes and edges visited by a lookup of the key.
+	Highlight string
+
+	// FormatValue formats the values of keys, defaults to the default format of the fmt package.
+	FormatValue func(value interface{}) string
+}
+
+// graphNode is a node of a graph export. Its ID is the path of the node in the Tree, the collapsed subtree of a node
+// has the ID of the node followed by '+', which is unique as the nodes of the subtree are not part of the graph.
+type graphNode struct {
+	ID    string `json:"id"`
+	Value string `json:"value,omitempty"`
+
+	Root        bool `json:"root,omitempty"`
+	Key         bool `json:"key,omitempty"`
+	Leaf        bool `json:"leaf,omitempty"`
+	Compressed  bool `json:"compressed,omitempty"`
+	Highlighted bool `json:"highlighted,omitempty"`
+
+	// Collapsed is the number of keys of a collapsed subtree, zero for all other nodes.
+	Collapsed int `json:"collapsed,omitempty"`
+}
+
+// graphEdge is an edge of a graph export from the node with the ID From to the node with the ID To.
+type graphEdge struct {
+	From  string `json:"from"`
+	To    string `json:"to"`
+	Label string `json:"label,omitempty"`
+
+	Compressed  bool `json:"compressed,omitempty"`
+	Highlighted bool `json:"highlighted,omitempty"`
+	Collapsed   bool `json:"collapsed,omitempty"`
+}
+
+// ToGraphJSON walks the Tree and converts it into a JSON object with the lists of its "nodes" and "edges", for web
+// visualizers. The nodes are identified by their path in the Tree and flag if they are the "root", a "key" with its
+// formatted "value", a "leaf" or "compressed". The edges connect the nodes "from" and "to" with their "label".
+func (t *Tree) ToGraphJSON() ([]byte, error) {
+	ret := struct {
+		Nodes []graphNode `json:"nodes"`
+		Edges []graphEdge `json:"edges"`
+	}{
+		Nodes: []graphNode{},
+		Edges: []graphEdge{},
+	}
+
+	t.graph(GraphOptions{}, func(n graphNode, edges []graphEdge) {
+		ret.Nodes = append(ret.Nodes, n)
+		ret.Edges = append(ret.Edges, edges...)
+	})
+
+	return json.Marshal(ret)
+}
+
+// graph walks the nodes of the Tree to export as graph, in reverse lexicographical order to keep the graphs stable.
+// The function is called for every node with its outgoing edges.
+func (t *Tree) graph(opts GraphOptions, fn func(n graphNode, edges []graphEdge)) {
+	t.expire()
+
+	format := opts.FormatValue
+	if format == nil {
+		format = func(value interface{}) string {
+			return fmt.Sprint(value)
+		}
+	}
+
+	start, key := &t.root, ""
+	if opts.Prefix != "" {
+		start, key = t.subtree(t.normalizeKey(opts.Prefix))
+		if start == nil {
+			return
+		}
+	}
+
+	// the nodes visited by a lookup of the highlighted key
+	visited := map[*node]bool{}
+	if opts.Highlight != "" {
+		t.find(t.normalizeKey(opts.Highlight), func(_ string, n *node) bool {
+			visited[n] = true
+			return false
+		})
+	}
+
+	type item struct {
+		node  *node
+		key   string
+		depth int
+
+		// collapsed is set for the collapsed subtree below the node
+		collapsed bool
+	}
+
+	items := []item{{node: start, key: key}}
+	for len(items) > 0 {
+		current := items[len(items)-1]
+		items = items[:len(items)-1]
+
+		n := current.node
+		if current.collapsed {
+			var counter int
+			for _, child := range n.children {
+				walk(child, nil, func(_ []byte, n *node) bool {
+					if n.isKey() {
+						counter += 1
+					}
+					return false
+				})
+			}
+
+			fn(graphNode{ID: current.key, Collapsed: counter}, nil)
+			continue
+		}
+
+		v := graphNode{
+			ID:          current.key,
+			Root:        n == start,
+			Key:         n.isKey(),
+			Leaf:        n.isLeaf(),
+			Compressed:  n.isCompressed(),
+			Highlighted: visited[n],
+		}
+		if v.Key {
+			v.Value = format(n.getValue())
+		}
+
+		var edges []graphEdge
+		switch {
+		case len(n.children) == 0:
+		case opts.MaxDepth > 0 && current.depth >= opts.MaxDepth:
+			edges = append(edges, graphEdge{From: current.key, To: current.key + "+", Collapsed: true})
+			items = append(items, item{node: n, key: current.key + "+", collapsed: true})
+		case n.isCompressed():
+			edges = append(edges, graphEdge{
+				From:        current.key,
+				To:          current.key + n.key,
+				Label:       n.key,
+				Compressed:  true,
+				Highlighted: v.Highlighted && visited[n.children[0]],
+			})
+			items = append(items, item{node: n.children[0], key: current.key + n.key, depth: current.depth + 1})
+		default:
+			for i, child := range n.children {
+				edges = append(edges, graphEdge{
+					From:        current.key,
+					To:          current.key + n.key[i:i+1],
+					Label:       n.key[i : i+1],
+					Highlighted: v.Highlighted && visited[child],
+				})
+				items = append(items, item{node: child, key: current.key + n.key[i:i+1], depth: current.depth + 1})
+			}
+		}
+
+		fn(v, edges)
+	}
+}
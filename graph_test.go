@@ -0,0 +1,74 @@
+package gorax_test
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/snorwin/gorax"
+)
+
+var _ = Describe("Tree", func() {
+	Context("ToGraphJSON", func() {
+		It("should_generate_empty_graph", func() {
+			Ω(gorax.New().ToGraphJSON()).Should(MatchJSON(`{"nodes":[{"id":"","root":true,"leaf":true}],"edges":[]}`))
+		})
+		It("should_generate_graph", func() {
+			t := gorax.FromMap(map[string]interface{}{
+				"foo":    1,
+				"foobar": 2,
+				"bar":    nil,
+			})
+
+			Ω(t.ToGraphJSON()).Should(MatchJSON(`{
+				"nodes": [
+					{"id": "", "root": true},
+					{"id": "f", "compressed": true},
+					{"id": "foo", "key": true, "value": "1", "compressed": true},
+					{"id": "foobar", "key": true, "value": "2", "leaf": true},
+					{"id": "b", "compressed": true},
+					{"id": "bar", "key": true, "value": "<nil>", "leaf": true}
+				],
+				"edges": [
+					{"from": "", "to": "b", "label": "b"},
+					{"from": "", "to": "f", "label": "f"},
+					{"from": "f", "to": "foo", "label": "oo", "compressed": true},
+					{"from": "foo", "to": "foobar", "label": "bar", "compressed": true},
+					{"from": "b", "to": "bar", "label": "ar", "compressed": true}
+				]
+			}`))
+		})
+		It("should_match_nodes_of_edges", func() {
+			t := gorax.New()
+			for _, key := range []string{"alien", "all", "alligator", "ba", "baloon", "rub", "ruber", "rubens"} {
+				t.Insert(key, nil)
+			}
+
+			data, err := t.ToGraphJSON()
+			Ω(err).ShouldNot(HaveOccurred())
+
+			var graph struct {
+				Nodes []struct {
+					ID string `json:"id"`
+				} `json:"nodes"`
+				Edges []struct {
+					From  string `json:"from"`
+					To    string `json:"to"`
+					Label string `json:"label"`
+				} `json:"edges"`
+			}
+			Ω(json.Unmarshal(data, &graph)).Should(Succeed())
+
+			ids := map[string]bool{}
+			for _, n := range graph.Nodes {
+				ids[n.ID] = true
+			}
+			Ω(graph.Edges).Should(HaveLen(len(graph.Nodes) - 1))
+			for _, e := range graph.Edges {
+				Ω(ids).Should(HaveKey(e.From))
+				Ω(e.To).Should(Equal(e.From + e.Label))
+			}
+		})
+	})
+})
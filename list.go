@@ -0,0 +1,166 @@
+package gorax
+
+import "strings"
+
+// DefaultMaxKeys is the number of keys and common prefixes returned by List if ListOptions.MaxKeys is not set.
+const DefaultMaxKeys = 1000
+
+// ListOptions are the parameters of List, modeled after the S3 ListObjectsV2 request.
+type ListOptions struct {
+	// Prefix limits the result to keys starting with the prefix.
+	Prefix string
+
+	// Delimiter groups all keys containing the delimiter after the prefix into a single common prefix, which is the
+	// key up to and including the first occurrence of the delimiter after the prefix.
+	Delimiter string
+
+	// StartAfter limits the result to keys and common prefixes of keys after this key.
+	StartAfter string
+
+	// MaxKeys is the maximum number of keys and common prefixes returned, defaults to DefaultMaxKeys.
+	MaxKeys int
+
+	// ContinuationToken continues a truncated listing, it takes precedence over StartAfter.
+	ContinuationToken string
+}
+
+// ListResult is the result of List.
+type ListResult struct {
+	// Keys are the sorted keys which are not grouped into a common prefix.
+	Keys []string
+
+	// CommonPrefixes are the sorted common prefixes of the keys containing the delimiter.
+	CommonPrefixes []string
+
+	// IsTruncated is 'true' if there are more keys or common prefixes to list.
+	IsTruncated bool
+
+	// NextContinuationToken is set if the result is truncated and continues the listing if used as
+	// ListOptions.ContinuationToken.
+	NextContinuationToken string
+}
+
+// List lists the keys under a prefix in lexicographical order like the S3 ListObjectsV2 API. Once a common prefix
+// is found, its subtree is skipped instead of being walked.
+func (t *Tree) List(opts ListOptions) ListResult {
+	t.expire()
+
+	opts.Prefix = t.normalizeKey(opts.Prefix)
+	opts.StartAfter = t.normalizeKey(opts.StartAfter)
+
+	l := lister{
+		ListOptions: opts,
+		after:       opts.StartAfter,
+	}
+	if l.MaxKeys <= 0 {
+		l.MaxKeys = DefaultMaxKeys
+	}
+	if opts.ContinuationToken != "" {
+		// the token is the last returned key or common prefix, all keys of a common prefix were returned with it
+		l.after = opts.ContinuationToken
+		l.afterCommonPrefix = l.commonPrefix(opts.ContinuationToken) == opts.ContinuationToken
+	}
+
+	if current, key := t.findSubtree(opts.Prefix, nil); current != nil {
+		l.list(current, key)
+	}
+
+	return l.result
+}
+
+type lister struct {
+	ListOptions
+
+	after             string
+	afterCommonPrefix bool
+
+	count  int
+	last   string
+	result ListResult
+}
+
+// list lists the subtree of a node in lexicographical order, returns 'true' if the listing is complete.
+func (l *lister) list(n *node, key string) bool {
+	// all keys of the subtree are before the start
+	if key < l.after && !strings.HasPrefix(l.after, key) {
+		return false
+	}
+
+	// all keys of the subtree are grouped into a common prefix
+	if prefix := l.commonPrefix(key); prefix != "" {
+		if prefix > l.after || (!l.afterCommonPrefix && strings.HasPrefix(l.after, prefix) && maximum(n, key) > l.after) {
+			return l.add(prefix, true)
+		}
+
+		return false
+	}
+
+	if n.isKey() && key > l.after {
+		if l.add(key, false) {
+			return true
+		}
+	}
+
+	if n.isCompressed() {
+		return l.list(n.children[0], key+n.key)
+	}
+
+	for i := range n.children {
+		if l.list(n.children[i], key+n.key[i:i+1]) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// add adds a key or common prefix to the result, returns 'true' if the result is truncated.
+func (l *lister) add(key string, commonPrefix bool) bool {
+	if l.count == l.MaxKeys {
+		l.result.IsTruncated = true
+		l.result.NextContinuationToken = l.last
+
+		return true
+	}
+
+	if commonPrefix {
+		l.result.CommonPrefixes = append(l.result.CommonPrefixes, key)
+	} else {
+		l.result.Keys = append(l.result.Keys, key)
+	}
+
+	l.count += 1
+	l.last = key
+
+	return false
+}
+
+// commonPrefix returns the key up to and including the first delimiter after the prefix, or an empty string if the
+// key does not contain the delimiter.
+func (l *lister) commonPrefix(key string) string {
+	if l.Delimiter == "" || !strings.HasPrefix(key, l.Prefix) {
+		return ""
+	}
+
+	i := strings.Index(key[len(l.Prefix):], l.Delimiter)
+	if i < 0 {
+		return ""
+	}
+
+	return key[:len(l.Prefix)+i+len(l.Delimiter)]
+}
+
+// maximum returns the largest key in the subtree of a node.
+func maximum(n *node, key string) string {
+	for len(n.key) > 0 {
+		if n.isCompressed() {
+			key += n.key
+			n = n.children[0]
+		} else {
+			key += n.key[len(n.key)-1:]
+			n = n.children[len(n.children)-1]
+		}
+	}
+
+	return key
+}
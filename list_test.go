@@ -0,0 +1,161 @@
+package gorax_test
+
+import (
+	"math/rand"
+	"sort"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/snorwin/gorax"
+)
+
+// list is a reference implementation of List on a sorted slice of keys.
+func list(keys []string, opts gorax.ListOptions) gorax.ListResult {
+	var ret gorax.ListResult
+
+	after := opts.StartAfter
+	if opts.ContinuationToken != "" {
+		after = opts.ContinuationToken
+	}
+
+	var last string
+	for _, key := range keys {
+		if !strings.HasPrefix(key, opts.Prefix) {
+			continue
+		}
+
+		item, commonPrefix := key, false
+		if i := strings.Index(key[len(opts.Prefix):], opts.Delimiter); opts.Delimiter != "" && i >= 0 {
+			item, commonPrefix = key[:len(opts.Prefix)+i+len(opts.Delimiter)], true
+		}
+
+		if key <= after || item == last || (opts.ContinuationToken != "" && item == after) {
+			continue
+		}
+
+		if len(ret.Keys)+len(ret.CommonPrefixes) == opts.MaxKeys {
+			ret.IsTruncated = true
+			ret.NextContinuationToken = last
+			break
+		}
+
+		if commonPrefix {
+			ret.CommonPrefixes = append(ret.CommonPrefixes, item)
+		} else {
+			ret.Keys = append(ret.Keys, item)
+		}
+		last = item
+	}
+
+	return ret
+}
+
+var _ = Describe("Tree", func() {
+	Context("List", func() {
+		var (
+			t *gorax.Tree
+		)
+		BeforeEach(func() {
+			t = gorax.FromMap(map[string]interface{}{
+				"photos/2006/january/sample.jpg":   1,
+				"photos/2006/february/sample.jpg":  2,
+				"photos/2006/february/sample2.jpg": 3,
+				"photos/2007/march/sample.jpg":     4,
+				"photos/index.html":                5,
+				"videos/2006/sample.mp4":           6,
+				"index.html":                       7,
+			})
+		})
+		It("should_not_fail_if_empty", func() {
+			Ω(gorax.New().List(gorax.ListOptions{})).Should(Equal(gorax.ListResult{}))
+		})
+		It("should_list_all_keys", func() {
+			result := t.List(gorax.ListOptions{})
+			Ω(result.Keys).Should(HaveLen(7))
+			Ω(sort.StringsAreSorted(result.Keys)).Should(BeTrue())
+			Ω(result.IsTruncated).Should(BeFalse())
+		})
+		It("should_list_common_prefixes", func() {
+			result := t.List(gorax.ListOptions{
+				Prefix:    "photos/",
+				Delimiter: "/",
+			})
+			Ω(result.Keys).Should(Equal([]string{"photos/index.html"}))
+			Ω(result.CommonPrefixes).Should(Equal([]string{"photos/2006/", "photos/2007/"}))
+		})
+		It("should_list_prefix_ending_in_compressed_node", func() {
+			result := t.List(gorax.ListOptions{
+				Prefix:    "photos/2006/feb",
+				Delimiter: "/",
+			})
+			Ω(result.CommonPrefixes).Should(Equal([]string{"photos/2006/february/"}))
+		})
+		It("should_start_after_key", func() {
+			result := t.List(gorax.ListOptions{
+				Delimiter:  "/",
+				StartAfter: "photos/2006/january/sample.jpg",
+			})
+			Ω(result.Keys).Should(BeEmpty())
+			Ω(result.CommonPrefixes).Should(Equal([]string{"photos/", "videos/"}))
+		})
+		It("should_continue_truncated_listing", func() {
+			result := t.List(gorax.ListOptions{
+				Delimiter: "/",
+				MaxKeys:   2,
+			})
+			Ω(result.Keys).Should(Equal([]string{"index.html"}))
+			Ω(result.CommonPrefixes).Should(Equal([]string{"photos/"}))
+			Ω(result.IsTruncated).Should(BeTrue())
+
+			result = t.List(gorax.ListOptions{
+				Delimiter:         "/",
+				MaxKeys:           2,
+				ContinuationToken: result.NextContinuationToken,
+			})
+			Ω(result.Keys).Should(BeEmpty())
+			Ω(result.CommonPrefixes).Should(Equal([]string{"videos/"}))
+			Ω(result.IsTruncated).Should(BeFalse())
+		})
+		It("should_match_reference_implementation", func() {
+			for i := 0; i < 100; i++ {
+				keys := make([]string, 200)
+				for j := range keys {
+					keys[j] = strings.Map(func(r rune) rune {
+						return rune("ab/"[rand.Intn(3)])
+					}, randString(rand.Intn(12)))
+				}
+
+				t := gorax.New()
+				for _, key := range keys {
+					t.Insert(key, nil)
+				}
+				keys = keys[:0]
+				t.Walk(func(key string, _ interface{}) bool {
+					keys = append(keys, key)
+
+					return false
+				})
+				sort.Strings(keys)
+
+				opts := gorax.ListOptions{
+					Prefix:     []string{"", "a", "ab", "a/"}[rand.Intn(4)],
+					Delimiter:  []string{"", "/", "b/"}[rand.Intn(3)],
+					StartAfter: []string{"", "a", "ab/a", "b"}[rand.Intn(4)],
+					MaxKeys:    1 + rand.Intn(10),
+				}
+				for {
+					expected := list(keys, opts)
+					actual := t.List(opts)
+					Ω(actual).Should(Equal(expected))
+
+					if !actual.IsTruncated {
+						break
+					}
+					opts.ContinuationToken = actual.NextContinuationToken
+				}
+			}
+		})
+	})
+})
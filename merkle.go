@@ -0,0 +1,331 @@
+package gorax
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"math"
+	"strings"
+)
+
+// ErrInvalidValue is returned by DecodeValue if the data was not encoded by EncodeValue.
+var ErrInvalidValue = errors.New("gorax: invalid encoded value")
+
+// Encoder encodes a value to be hashed by a Tree configured WithHash.
+type Encoder func(value interface{}) []byte
+
+// The type tags of EncodeValue.
+const (
+	tagNil byte = iota
+	tagBytes
+	tagString
+	tagBool
+	tagInt
+	tagUint
+	tagFloat
+)
+
+// EncodeValue is the default Encoder, it encodes nil, byte slices, strings, booleans, integers and floats prefixed with
+// a tag of their type, so that e.g. 1 and "1" are encoded differently. Integers of all sizes are encoded alike, as are
+// floats. It panics for values of any other type, which require an explicit Encoder.
+func EncodeValue(value interface{}) []byte {
+	switch v := value.(type) {
+	case nil:
+		return []byte{tagNil}
+	case []byte:
+		return append([]byte{tagBytes}, v...)
+	case string:
+		return append([]byte{tagString}, v...)
+	case bool:
+		if v {
+			return []byte{tagBool, 1}
+		}
+		return []byte{tagBool, 0}
+	case int:
+		return binary.AppendVarint([]byte{tagInt}, int64(v))
+	case int8:
+		return binary.AppendVarint([]byte{tagInt}, int64(v))
+	case int16:
+		return binary.AppendVarint([]byte{tagInt}, int64(v))
+	case int32:
+		return binary.AppendVarint([]byte{tagInt}, int64(v))
+	case int64:
+		return binary.AppendVarint([]byte{tagInt}, v)
+	case uint:
+		return binary.AppendUvarint([]byte{tagUint}, uint64(v))
+	case uint8:
+		return binary.AppendUvarint([]byte{tagUint}, uint64(v))
+	case uint16:
+		return binary.AppendUvarint([]byte{tagUint}, uint64(v))
+	case uint32:
+		return binary.AppendUvarint([]byte{tagUint}, uint64(v))
+	case uint64:
+		return binary.AppendUvarint([]byte{tagUint}, v)
+	case float32:
+		return binary.BigEndian.AppendUint64([]byte{tagFloat}, math.Float64bits(float64(v)))
+	case float64:
+		return binary.BigEndian.AppendUint64([]byte{tagFloat}, math.Float64bits(v))
+	default:
+		panic(unsupportedValue(value))
+	}
+}
+
+// checkValue panics if EncodeValue does not support the type of a value. It is called by Insert of a Tree hashing its
+// values with EncodeValue, to fail at the insert of the value instead of in RootHash, Prove or Sync.
+func checkValue(value interface{}) {
+	switch value.(type) {
+	case nil, []byte, string, bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+	default:
+		panic(unsupportedValue(value))
+	}
+}
+
+func unsupportedValue(value interface{}) string {
+	return fmt.Sprintf("gorax: EncodeValue does not support values of type %T, use WithHash with an Encoder", value)
+}
+
+// DecodeValue decodes a value encoded by EncodeValue. Integers are decoded as int or uint and floats as float64.
+func DecodeValue(data []byte) (interface{}, error) {
+	if len(data) == 0 {
+		return nil, ErrInvalidValue
+	}
+
+	tag, data := data[0], data[1:]
+	switch tag {
+	case tagNil:
+		if len(data) == 0 {
+			return nil, nil
+		}
+	case tagBytes:
+		return append([]byte{}, data...), nil
+	case tagString:
+		return string(data), nil
+	case tagBool:
+		if len(data) == 1 && data[0] <= 1 {
+			return data[0] == 1, nil
+		}
+	case tagInt:
+		if v, n := binary.Varint(data); n > 0 && n == len(data) {
+			return int(v), nil
+		}
+	case tagUint:
+		if v, n := binary.Uvarint(data); n > 0 && n == len(data) {
+			return uint(v), nil
+		}
+	case tagFloat:
+		if len(data) == 8 {
+			return math.Float64frombits(binary.BigEndian.Uint64(data)), nil
+		}
+	}
+
+	return nil, ErrInvalidValue
+}
+
+// Proof is an inclusion or exclusion proof of a key created by Prove. It contains the nodes on the path from the root
+// down to the node of the key, or to the node where the key leaves the Tree.
+type Proof []ProofNode
+
+// ProofNode is a node of a Proof.
+type ProofNode struct {
+	// ValueHash is the SHA-256 hash of the encoded value if the node is a key, nil otherwise.
+	ValueHash []byte
+
+	// Labels are the edge labels to the children of the node.
+	Labels []string
+
+	// Hashes are the hashes of the children of the node, except the hash of the next node of the Proof which is nil.
+	Hashes [][]byte
+
+	// Next is the index of the child which is the next node of the Proof, -1 for the last node.
+	Next int
+}
+
+// RootHash returns the hash of the Tree, which is equal for Trees with the same keys and values regardless of the
+// order of their mutations. Returns nil if the Tree is not configured WithHash.
+func (t *Tree) RootHash() []byte {
+	if t.encode == nil {
+		return nil
+	}
+
+	t.expire()
+
+	return append([]byte(nil), t.hash(&t.root)...)
+}
+
+// Prove returns a Proof that the key is in the Tree with its current value, or that it is not in the Tree, and
+// 'true' if the key is in the Tree. Returns nil if the Tree is not configured WithHash.
+func (t *Tree) Prove(key string) (Proof, bool) {
+	if t.encode == nil {
+		return nil, false
+	}
+
+	t.expire()
+
+	key = t.normalizeKey(key)
+	t.hash(&t.root)
+
+	var proof Proof
+
+	current := &t.root
+	for idx := 0; ; {
+		p := ProofNode{
+			Labels: labels(current),
+			Hashes: make([][]byte, len(current.children)),
+			Next:   -1,
+		}
+		if current.isKey() {
+			p.ValueHash = hashValue(t.encode(current.getValue()))
+		}
+		for i, child := range current.children {
+			p.Hashes[i] = t.hash(child)
+		}
+
+		// find the child the key continues with
+		next := -1
+		if idx < len(key) {
+			for i, label := range p.Labels {
+				if strings.HasPrefix(key[idx:], label) {
+					next = i
+					break
+				}
+			}
+		}
+		if next < 0 {
+			return append(proof, p), idx == len(key) && current.isKey()
+		}
+
+		p.Hashes[next], p.Next = nil, next
+		proof = append(proof, p)
+
+		idx += len(p.Labels[next])
+		current = current.children[next]
+	}
+}
+
+// VerifyProof verifies a Proof of a key against the root hash of a Tree. If the value is not nil, it verifies that the
+// key is in the Tree with the encoded value, otherwise it verifies that the key is not in the Tree.
+func VerifyProof(rootHash []byte, key string, value []byte, proof Proof) bool {
+	if len(proof) == 0 {
+		return false
+	}
+
+	// follow the key along the labels of the path
+	var idx int
+	for _, p := range proof[:len(proof)-1] {
+		if p.Next < 0 || p.Next >= len(p.Labels) || !strings.HasPrefix(key[idx:], p.Labels[p.Next]) {
+			return false
+		}
+		idx += len(p.Labels[p.Next])
+	}
+
+	last := proof[len(proof)-1]
+	switch {
+	case value != nil:
+		if idx != len(key) || !bytes.Equal(last.ValueHash, hashValue(value)) {
+			return false
+		}
+	case idx == len(key):
+		if last.ValueHash != nil {
+			return false
+		}
+	default:
+		for _, label := range last.Labels {
+			if strings.HasPrefix(key[idx:], label) {
+				return false
+			}
+		}
+	}
+
+	// hash the path from the last node up to the root
+	var h []byte
+	for i := len(proof) - 1; i >= 0; i-- {
+		p := proof[i]
+		if len(p.Labels) != len(p.Hashes) || (p.ValueHash != nil && len(p.ValueHash) != sha256.Size) {
+			return false
+		}
+
+		hashes := p.Hashes
+		if i < len(proof)-1 {
+			hashes = append([][]byte(nil), p.Hashes...)
+			hashes[p.Next] = h
+		}
+		for _, childHash := range hashes {
+			if len(childHash) != sha256.Size {
+				return false
+			}
+		}
+
+		h = hashNode(p.ValueHash, p.Labels, hashes)
+	}
+
+	return bytes.Equal(h, rootHash)
+}
+
+// hash returns the hash of a node and updates it first if it was invalidated.
+func (t *Tree) hash(n *node) []byte {
+	c := n.caches()
+	if !c.hashed {
+		var valueHash []byte
+		if n.isKey() {
+			valueHash = hashValue(t.encode(n.getValue()))
+		}
+
+		hashes := make([][]byte, len(n.children))
+		for i, child := range n.children {
+			hashes[i] = t.hash(child)
+		}
+
+		c.hash = hashNode(valueHash, labels(n), hashes)
+		c.hashed = true
+	}
+
+	return c.hash
+}
+
+// labels returns the edge labels to the children of a node.
+func labels(n *node) []string {
+	if n.isCompressed() {
+		return []string{n.key}
+	}
+
+	ret := make([]string, len(n.key))
+	for i := range ret {
+		ret[i] = n.key[i : i+1]
+	}
+
+	return ret
+}
+
+func hashValue(value []byte) []byte {
+	h := sha256.Sum256(value)
+
+	return h[:]
+}
+
+// hashNode hashes the value hash of a node, or a marker if it is not a key, followed by the length prefixed edge label
+// and the hash of every child.
+func hashNode(valueHash []byte, labels []string, hashes [][]byte) []byte {
+	h := sha256.New()
+	if valueHash != nil {
+		h.Write([]byte{1})
+		h.Write(valueHash)
+	} else {
+		h.Write([]byte{0})
+	}
+
+	for i := range labels {
+		writeLabel(h, labels[i])
+		h.Write(hashes[i])
+	}
+
+	return h.Sum(nil)
+}
+
+func writeLabel(h hash.Hash, label string) {
+	var buf [binary.MaxVarintLen64]byte
+	h.Write(buf[:binary.PutUvarint(buf[:], uint64(len(label)))])
+	h.Write([]byte(label))
+}
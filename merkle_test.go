@@ -0,0 +1,163 @@
+package gorax_test
+
+import (
+	"math/rand"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/snorwin/gorax"
+)
+
+var _ = Describe("Tree", func() {
+	Context("WithHash", func() {
+		var (
+			t *gorax.Tree
+			m map[string]interface{}
+		)
+		BeforeEach(func() {
+			m = map[string]interface{}{
+				"foo":    1,
+				"foobar": "bar",
+				"foojin": nil,
+				"bar":    []byte{4},
+			}
+			t = gorax.FromMap(m, gorax.WithHash(nil))
+		})
+		It("should_not_hash_without_option", func() {
+			Ω(gorax.New().RootHash()).Should(BeNil())
+
+			proof, ok := gorax.New().Prove("foo")
+			Ω(proof).Should(BeNil())
+			Ω(ok).Should(BeFalse())
+		})
+		It("should_not_depend_on_order_of_mutations", func() {
+			other := gorax.New(gorax.WithHash(nil))
+			other.Insert("fo", 0)
+			other.Insert("bar", []byte{4})
+			other.Insert("foojin", nil)
+			other.Insert("foobar", "bar")
+			other.Insert("foo", 1)
+			Ω(other.RootHash()).ShouldNot(Equal(t.RootHash()))
+
+			other.Delete("fo")
+			Ω(other.RootHash()).Should(Equal(t.RootHash()))
+		})
+		It("should_encode_type_of_values", func() {
+			Ω(gorax.EncodeValue(1)).ShouldNot(Equal(gorax.EncodeValue("1")))
+			Ω(gorax.EncodeValue("")).ShouldNot(Equal(gorax.EncodeValue(nil)))
+			Ω(gorax.EncodeValue("bar")).ShouldNot(Equal(gorax.EncodeValue([]byte("bar"))))
+			Ω(gorax.EncodeValue(int8(-3))).Should(Equal(gorax.EncodeValue(-3)))
+
+			other := gorax.New(gorax.WithHash(nil))
+			other.Insert("foo", "1")
+			other.Insert("foobar", "bar")
+			other.Insert("foojin", nil)
+			other.Insert("bar", []byte{4})
+			Ω(other.RootHash()).ShouldNot(Equal(t.RootHash()))
+
+			value := 1
+			Ω(func() { gorax.EncodeValue(&value) }).Should(Panic())
+			Ω(func() { gorax.EncodeValue(struct{}{}) }).Should(Panic())
+		})
+		It("should_reject_unsupported_values_on_insert", func() {
+			value := 1
+			Ω(func() { t.Insert("foo", &value) }).Should(Panic())
+			Ω(t.ToMap()).Should(Equal(m))
+			Ω(func() { t.RootHash() }).ShouldNot(Panic())
+
+			other := gorax.New(gorax.WithHash(func(value interface{}) []byte {
+				return []byte{byte(*value.(*int))}
+			}))
+			Ω(func() { other.Insert("foo", &value) }).ShouldNot(Panic())
+			Ω(other.RootHash()).Should(HaveLen(32))
+		})
+		It("should_decode_encoded_values", func() {
+			decoded, err := gorax.DecodeValue(gorax.EncodeValue(nil))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(decoded).Should(BeNil())
+
+			for _, value := range []interface{}{[]byte{}, []byte("foo"), "", "bar", true, false, -42, uint(42), 0.5} {
+				decoded, err := gorax.DecodeValue(gorax.EncodeValue(value))
+				Ω(err).ShouldNot(HaveOccurred())
+				Ω(decoded).Should(Equal(value))
+			}
+
+			for _, data := range [][]byte{nil, {0, 1}, {3, 2}, {4}, {6, 1}, {42}} {
+				_, err := gorax.DecodeValue(data)
+				Ω(err).Should(MatchError(gorax.ErrInvalidValue))
+			}
+		})
+		It("should_update_hash_on_mutation", func() {
+			hash := t.RootHash()
+			Ω(hash).Should(HaveLen(32))
+
+			t.Insert("foo", 2)
+			Ω(t.RootHash()).ShouldNot(Equal(hash))
+
+			t.Insert("foo", 1)
+			Ω(t.RootHash()).Should(Equal(hash))
+
+			t.DeletePrefix("foob")
+			Ω(t.RootHash()).ShouldNot(Equal(hash))
+
+			t.Insert("foobar", "bar")
+			Ω(t.RootHash()).Should(Equal(hash))
+		})
+		It("should_prove_inclusion", func() {
+			hash := t.RootHash()
+			for key, value := range m {
+				proof, ok := t.Prove(key)
+				Ω(ok).Should(BeTrue())
+				Ω(gorax.VerifyProof(hash, key, gorax.EncodeValue(value), proof)).Should(BeTrue())
+				Ω(gorax.VerifyProof(hash, key, nil, proof)).Should(BeFalse())
+				Ω(gorax.VerifyProof(hash, key, []byte("other"), proof)).Should(BeFalse())
+			}
+
+			proof, _ := t.Prove("foo")
+			Ω(gorax.VerifyProof(hash, "fox", gorax.EncodeValue(1), proof)).Should(BeFalse())
+
+			t.Insert("foo", 2)
+			Ω(gorax.VerifyProof(t.RootHash(), "foo", gorax.EncodeValue(1), proof)).Should(BeFalse())
+		})
+		It("should_prove_exclusion", func() {
+			hash := t.RootHash()
+			for _, key := range []string{"", "f", "fo", "foob", "fooba", "foobarr", "baz", "x"} {
+				proof, ok := t.Prove(key)
+				Ω(ok).Should(BeFalse())
+				Ω(gorax.VerifyProof(hash, key, nil, proof)).Should(BeTrue(), key)
+			}
+
+			proof, _ := t.Prove("foo")
+			Ω(gorax.VerifyProof(hash, "foo", nil, proof)).Should(BeFalse())
+
+			proof, _ = t.Prove("fooba")
+			Ω(gorax.VerifyProof(hash, "foobar", nil, proof)).Should(BeFalse())
+		})
+		It("should_verify_random_proofs", func() {
+			t := gorax.New(gorax.WithHash(nil))
+			for i := 0; i < 1000; i++ {
+				t.Insert(randString(rand.Intn(8)), i)
+				if i%3 == 0 {
+					t.DeletePrefix(randString(rand.Intn(4)))
+				}
+			}
+
+			hash := t.RootHash()
+			Ω(gorax.FromMap(t.ToMap(), gorax.WithHash(nil)).RootHash()).Should(Equal(hash))
+
+			for i := 0; i < 1000; i++ {
+				key := randString(rand.Intn(8))
+				value, included := t.Get(key)
+
+				proof, ok := t.Prove(key)
+				Ω(ok).Should(Equal(included))
+				if included {
+					Ω(gorax.VerifyProof(hash, key, gorax.EncodeValue(value), proof)).Should(BeTrue())
+				} else {
+					Ω(gorax.VerifyProof(hash, key, nil, proof)).Should(BeTrue())
+				}
+			}
+		})
+	})
+})
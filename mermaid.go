@@ -0,0 +1,77 @@
+package gorax
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ToMermaid walks the Tree and converts it into a Mermaid flowchart, to be embedded in Markdown. Like ToDOTGraph,
+// leaf nodes are blue, compressed nodes and edges green and keys are rectangles labeled with their value.
+func (t *Tree) ToMermaid() string {
+	var nodes, edges []string
+	var leafs, compressed, greenEdges []string
+
+	// the nodes are numbered in the order they are reached
+	ids := map[string]string{}
+	id := func(key string) string {
+		if _, ok := ids[key]; !ok {
+			ids[key] = fmt.Sprintf("n%d", len(ids)+1)
+		}
+		return ids[key]
+	}
+
+	t.graph(GraphOptions{}, func(v graphNode, out []graphEdge) {
+		n := id(v.ID)
+		switch {
+		case v.Root:
+			nodes = append(nodes, n+"(( ))")
+		case v.Key:
+			nodes = append(nodes, fmt.Sprintf(`%s["%s = %s"]`, n, escapeMermaid(v.ID), escapeMermaid(v.Value)))
+		default:
+			nodes = append(nodes, fmt.Sprintf(`%s("%s")`, n, escapeMermaid(v.ID)))
+		}
+
+		if v.Leaf {
+			leafs = append(leafs, n)
+		}
+		if v.Compressed {
+			compressed = append(compressed, n)
+		}
+
+		for _, edge := range out {
+			if edge.Compressed {
+				greenEdges = append(greenEdges, fmt.Sprint(len(edges)))
+			}
+			edges = append(edges, fmt.Sprintf(`%s -->|"%s"| %s`, n, escapeMermaid(edge.Label), id(edge.To)))
+		}
+	})
+
+	var b strings.Builder
+	b.WriteString("flowchart TD\n")
+	for _, line := range append(nodes, edges...) {
+		b.WriteString("\t" + line + "\n")
+	}
+	if len(leafs) > 0 {
+		b.WriteString("\tclassDef leaf stroke:blue\n")
+		b.WriteString("\tclass " + strings.Join(leafs, ",") + " leaf\n")
+	}
+	if len(compressed) > 0 {
+		b.WriteString("\tclassDef compressed stroke:green\n")
+		b.WriteString("\tclass " + strings.Join(compressed, ",") + " compressed\n")
+	}
+	if len(greenEdges) > 0 {
+		b.WriteString("\tlinkStyle " + strings.Join(greenEdges, ",") + " stroke:green\n")
+	}
+
+	return b.String()
+}
+
+// escapeMermaid escapes a string for a quoted label of Mermaid with its entity codes.
+var escapeMermaid = strings.NewReplacer(
+	`#`, "#35;",
+	`"`, "#quot;",
+	`&`, "#amp;",
+	`<`, "#lt;",
+	`>`, "#gt;",
+	"\n", "#92;n",
+).Replace
@@ -0,0 +1,49 @@
+package gorax_test
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/snorwin/gorax"
+)
+
+var _ = Describe("Tree", func() {
+	Context("ToMermaid", func() {
+		It("should_generate_flowchart", func() {
+			t := gorax.FromMap(map[string]interface{}{
+				"foo":    1,
+				"foobar": 2,
+				"bar":    nil,
+			})
+
+			Ω(t.ToMermaid()).Should(Equal(strings.Join([]string{
+				"flowchart TD",
+				"\tn1(( ))",
+				"\tn3(\"f\")",
+				"\tn4[\"foo = 1\"]",
+				"\tn5[\"foobar = 2\"]",
+				"\tn2(\"b\")",
+				"\tn6[\"bar = #lt;nil#gt;\"]",
+				"\tn1 -->|\"b\"| n2",
+				"\tn1 -->|\"f\"| n3",
+				"\tn3 -->|\"oo\"| n4",
+				"\tn4 -->|\"bar\"| n5",
+				"\tn2 -->|\"ar\"| n6",
+				"\tclassDef leaf stroke:blue",
+				"\tclass n5,n6 leaf",
+				"\tclassDef compressed stroke:green",
+				"\tclass n3,n4,n2 compressed",
+				"\tlinkStyle 2,3,4 stroke:green",
+				"",
+			}, "\n")))
+		})
+		It("should_escape_labels", func() {
+			t := gorax.New()
+			t.Insert("a\"#", "<b>&\n")
+
+			Ω(t.ToMermaid()).Should(ContainSubstring(`["a#quot;#35; = #lt;b#gt;#amp;#92;n"]`))
+		})
+	})
+})
@@ -0,0 +1,109 @@
+package gorax
+
+import "reflect"
+
+// MultiTree is a radix tree which maps every key to multiple values, e.g. tokens to the IDs of the documents containing
+// them.
+type MultiTree struct {
+	tree   *Tree
+	values int
+}
+
+// NewMultiTree returns an empty MultiTree configured with the given options, e.g. WithSeparator or WithNormalizer.
+// It panics if the options configure a capacity, as evicted keys would be missing from the number of values, or a
+// hash, as the values of a key are stored in a mutable slice.
+func NewMultiTree(opts ...Option) *MultiTree {
+	t := New(opts...)
+	if len(t.bounds) > 0 {
+		panic("gorax: MultiTree does not support capacities")
+	}
+	if t.encode != nil {
+		panic("gorax: MultiTree does not support hashes")
+	}
+
+	return &MultiTree{
+		tree: t,
+	}
+}
+
+// Len returns the number of distinct keys and the total number of values in the MultiTree.
+func (m *MultiTree) Len() (int, int) {
+	return m.tree.Len(), m.values
+}
+
+// Add appends a value to the values of a key.
+func (m *MultiTree) Add(key string, value interface{}) {
+	if values, ok := m.tree.Get(key); ok {
+		*values.(*[]interface{}) = append(*values.(*[]interface{}), value)
+	} else {
+		m.tree.Insert(key, &[]interface{}{value})
+	}
+
+	m.values += 1
+}
+
+// Get returns the values of a key in the order they were added, or nil if there are none.
+func (m *MultiTree) Get(key string) []interface{} {
+	values, ok := m.tree.Get(key)
+	if !ok {
+		return nil
+	}
+
+	return append([]interface{}(nil), *values.(*[]interface{})...)
+}
+
+// Remove removes the first value of a key deeply equal (reflect.DeepEqual) to the value, the key is deleted with its last value.
+// Returns 'true' if the value was removed.
+func (m *MultiTree) Remove(key string, value interface{}) bool {
+	v, ok := m.tree.Get(key)
+	if !ok {
+		return false
+	}
+
+	values := v.(*[]interface{})
+	for i := range *values {
+		if reflect.DeepEqual((*values)[i], value) {
+			*values = append((*values)[:i], (*values)[i+1:]...)
+			m.values -= 1
+
+			if len(*values) == 0 {
+				m.tree.Delete(key)
+			}
+
+			return true
+		}
+	}
+
+	return false
+}
+
+// Delete deletes a key and returns all its values.
+func (m *MultiTree) Delete(key string) []interface{} {
+	values, ok := m.tree.Delete(key)
+	if !ok {
+		return nil
+	}
+
+	m.values -= len(*values.(*[]interface{}))
+
+	return *values.(*[]interface{})
+}
+
+// Walk walks every key and value of the MultiTree in lexicographical order of the keys.
+func (m *MultiTree) Walk(fn WalkFn) {
+	m.WalkPrefix("", fn)
+}
+
+// WalkPrefix walks every key and value under a prefix in lexicographical order of the keys. A key is passed to the
+// WalkFn once for each of its values.
+func (m *MultiTree) WalkPrefix(prefix string, fn WalkFn) {
+	m.tree.WalkPrefix(prefix, func(key string, values interface{}) bool {
+		for _, value := range *values.(*[]interface{}) {
+			if fn(key, value) {
+				return true
+			}
+		}
+
+		return false
+	})
+}
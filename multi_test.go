@@ -0,0 +1,99 @@
+package gorax_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/snorwin/gorax"
+)
+
+var _ = Describe("MultiTree", func() {
+	var (
+		m *gorax.MultiTree
+	)
+	BeforeEach(func() {
+		m = gorax.NewMultiTree()
+		m.Add("foo", 1)
+		m.Add("foo", 2)
+		m.Add("foobar", 3)
+		m.Add("bar", 4)
+	})
+	It("should_not_fail_if_empty", func() {
+		m := gorax.NewMultiTree()
+		Ω(m.Get("foo")).Should(BeNil())
+		Ω(m.Remove("foo", 1)).Should(BeFalse())
+		Ω(m.Delete("foo")).Should(BeNil())
+
+		keys, values := m.Len()
+		Ω(keys).Should(BeZero())
+		Ω(values).Should(BeZero())
+	})
+	It("should_reject_unsupported_options", func() {
+		Ω(func() { gorax.NewMultiTree(gorax.WithCapacity(10)) }).Should(PanicWith("gorax: MultiTree does not support capacities"))
+		Ω(func() { gorax.NewMultiTree(gorax.WithPrefixCapacity("foo", 10)) }).Should(Panic())
+		Ω(func() { gorax.NewMultiTree(gorax.WithHash(nil)) }).Should(PanicWith("gorax: MultiTree does not support hashes"))
+	})
+	It("should_add_and_get_values", func() {
+		m.Add("foo", 1)
+		Ω(m.Get("foo")).Should(Equal([]interface{}{1, 2, 1}))
+
+		keys, values := m.Len()
+		Ω(keys).Should(Equal(3))
+		Ω(values).Should(Equal(5))
+	})
+	It("should_remove_value", func() {
+		Ω(m.Remove("foo", 1)).Should(BeTrue())
+		Ω(m.Remove("foo", 1)).Should(BeFalse())
+		Ω(m.Get("foo")).Should(Equal([]interface{}{2}))
+
+		Ω(m.Remove("foo", 2)).Should(BeTrue())
+		Ω(m.Get("foo")).Should(BeNil())
+
+		keys, values := m.Len()
+		Ω(keys).Should(Equal(2))
+		Ω(values).Should(Equal(2))
+	})
+	It("should_remove_uncomparable_value", func() {
+		m.Add("bar", []string{"jin"})
+		m.Add("bar", map[string]int{"jin": 5})
+
+		Ω(m.Remove("bar", []string{"jin"})).Should(BeTrue())
+		Ω(m.Remove("bar", map[string]int{"jin": 6})).Should(BeFalse())
+		Ω(m.Get("bar")).Should(Equal([]interface{}{4, map[string]int{"jin": 5}}))
+	})
+	It("should_delete_key", func() {
+		Ω(m.Delete("foo")).Should(Equal([]interface{}{1, 2}))
+
+		keys, values := m.Len()
+		Ω(keys).Should(Equal(2))
+		Ω(values).Should(Equal(2))
+	})
+	It("should_not_change_returned_values", func() {
+		values := m.Get("foo")
+		m.Remove("foo", 1)
+		Ω(values).Should(Equal([]interface{}{1, 2}))
+	})
+	It("should_walk_prefix", func() {
+		type pair struct {
+			key   string
+			value interface{}
+		}
+
+		var pairs []pair
+		m.WalkPrefix("foo", func(key string, value interface{}) bool {
+			pairs = append(pairs, pair{key, value})
+
+			return false
+		})
+		Ω(pairs).Should(Equal([]pair{{"foo", 1}, {"foo", 2}, {"foobar", 3}}))
+	})
+	It("should_walk_and_stop_after_first", func() {
+		var count int
+		m.Walk(func(_ string, _ interface{}) bool {
+			count += 1
+
+			return true
+		})
+		Ω(count).Should(Equal(1))
+	})
+})
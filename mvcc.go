@@ -0,0 +1,174 @@
+package gorax
+
+import "sort"
+
+// VersionedTree is a radix tree which keeps the history of every key, to read the Tree as it was at an earlier version.
+// Every mutation creates a new version. All versions of a key are stored in the node of the key, so they share its
+// path in the Tree.
+type VersionedTree struct {
+	tree    *Tree
+	version uint64
+	size    int
+}
+
+// revision is the value of a key since a version, or its deletion.
+type revision struct {
+	version uint64
+	value   interface{}
+	deleted bool
+}
+
+// NewVersionedTree returns an empty VersionedTree configured with the given options, e.g. WithSeparator or
+// WithNormalizer. It panics if the options configure a capacity or a hash, as they would apply to the whole history
+// of a key.
+func NewVersionedTree(opts ...Option) *VersionedTree {
+	t := New(opts...)
+	if len(t.bounds) > 0 {
+		panic("gorax: VersionedTree does not support capacities")
+	}
+	if t.encode != nil {
+		panic("gorax: VersionedTree does not support hashes")
+	}
+
+	return &VersionedTree{
+		tree: t,
+	}
+}
+
+// Version returns the version of the last mutation, 0 if there was none.
+func (v *VersionedTree) Version() uint64 {
+	return v.version
+}
+
+// Len returns the number of keys in the latest version.
+func (v *VersionedTree) Len() int {
+	return v.size
+}
+
+// Insert adds or updates a key and returns the version of the mutation.
+func (v *VersionedTree) Insert(key string, value interface{}) uint64 {
+	v.version += 1
+
+	revisions := v.revisions(key)
+	if revisions == nil {
+		v.tree.Insert(key, &[]revision{{version: v.version, value: value}})
+		v.size += 1
+
+		return v.version
+	}
+
+	if last := (*revisions)[len(*revisions)-1]; last.deleted {
+		v.size += 1
+	}
+	*revisions = append(*revisions, revision{version: v.version, value: value})
+
+	return v.version
+}
+
+// Delete deletes a key and returns the version of the mutation and if the key was deleted. The key is kept in the
+// history until it is compacted.
+func (v *VersionedTree) Delete(key string) (uint64, bool) {
+	revisions := v.revisions(key)
+	if revisions == nil || (*revisions)[len(*revisions)-1].deleted {
+		return v.version, false
+	}
+
+	v.version += 1
+	*revisions = append(*revisions, revision{version: v.version, deleted: true})
+	v.size -= 1
+
+	return v.version, true
+}
+
+// Get returns the latest value of a key and if it was found.
+func (v *VersionedTree) Get(key string) (interface{}, bool) {
+	return v.GetAt(key, v.version)
+}
+
+// GetAt returns the value of a key at a version and if it was found. The history before the version of the last
+// Compact is discarded, reading it only finds the keys which have not been changed since.
+func (v *VersionedTree) GetAt(key string, version uint64) (interface{}, bool) {
+	revisions := v.revisions(key)
+	if revisions == nil {
+		return nil, false
+	}
+
+	return at(*revisions, version)
+}
+
+// WalkPrefix walks the latest version of the keys under a prefix.
+func (v *VersionedTree) WalkPrefix(prefix string, fn WalkFn) {
+	v.WalkPrefixAt(prefix, v.version, fn)
+}
+
+// WalkPrefixAt walks the keys under a prefix as they were at a version.
+func (v *VersionedTree) WalkPrefixAt(prefix string, version uint64, fn WalkFn) {
+	v.tree.WalkPrefix(prefix, func(key string, revisions interface{}) bool {
+		value, ok := at(*revisions.(*[]revision), version)
+		if !ok {
+			return false
+		}
+
+		return fn(key, value)
+	})
+}
+
+// Compact discards the history before a version, only the revision of every key which is visible at the version is
+// kept. Keys deleted at the version without later revisions are removed from the Tree. Returns the number of discarded
+// revisions.
+func (v *VersionedTree) Compact(before uint64) int {
+	var counter int
+
+	var deleted []string
+	v.tree.Walk(func(key string, value interface{}) bool {
+		revisions := value.(*[]revision)
+
+		// index of the first revision after the version, the one before it is still visible at the version
+		i := sort.Search(len(*revisions), func(i int) bool { return (*revisions)[i].version > before })
+		if i > 1 {
+			*revisions = append((*revisions)[:0], (*revisions)[i-1:]...)
+			counter += i - 1
+		}
+		if i > 0 && (*revisions)[0].deleted {
+			// a deletion visible at the version is equal to no revision
+			*revisions = (*revisions)[1:]
+			counter += 1
+		}
+		if len(*revisions) == 0 {
+			deleted = append(deleted, key)
+		}
+
+		return false
+	})
+
+	for _, key := range deleted {
+		v.tree.Delete(key)
+	}
+
+	return counter
+}
+
+func (v *VersionedTree) revisions(key string) *[]revision {
+	value, ok := v.tree.Get(key)
+	if !ok {
+		return nil
+	}
+
+	return value.(*[]revision)
+}
+
+// at returns the value of the revisions at a version.
+func at(revisions []revision, version uint64) (interface{}, bool) {
+	i := sort.Search(len(revisions), func(i int) bool { return revisions[i].version > version })
+	if i == 0 {
+		// versions before the first revision, which is older than the version after a Compact
+		return nil, false
+	}
+
+	r := revisions[i-1]
+	if r.deleted {
+		return nil, false
+	}
+
+	return r.value, true
+}
@@ -0,0 +1,131 @@
+package gorax_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/snorwin/gorax"
+)
+
+var _ = Describe("VersionedTree", func() {
+	var (
+		v *gorax.VersionedTree
+	)
+	BeforeEach(func() {
+		v = gorax.NewVersionedTree()
+		v.Insert("foo", 1)    // 1
+		v.Insert("foobar", 2) // 2
+		v.Insert("foo", 3)    // 3
+		v.Delete("foobar")    // 4
+		v.Insert("bar", 5)    // 5
+	})
+	It("should_not_fail_if_empty", func() {
+		v := gorax.NewVersionedTree()
+		Ω(v.Version()).Should(BeZero())
+		Ω(v.Len()).Should(BeZero())
+
+		_, ok := v.Get("foo")
+		Ω(ok).Should(BeFalse())
+		_, ok = v.Delete("foo")
+		Ω(ok).Should(BeFalse())
+		Ω(v.Compact(1)).Should(BeZero())
+	})
+	It("should_reject_unsupported_options", func() {
+		Ω(func() { gorax.NewVersionedTree(gorax.WithCapacity(10)) }).Should(PanicWith("gorax: VersionedTree does not support capacities"))
+		Ω(func() { gorax.NewVersionedTree(gorax.WithPrefixCapacity("foo", 10)) }).Should(Panic())
+		Ω(func() { gorax.NewVersionedTree(gorax.WithHash(nil)) }).Should(PanicWith("gorax: VersionedTree does not support hashes"))
+	})
+	It("should_increase_version", func() {
+		Ω(v.Version()).Should(Equal(uint64(5)))
+		Ω(v.Insert("baz", 6)).Should(Equal(uint64(6)))
+
+		version, ok := v.Delete("baz")
+		Ω(ok).Should(BeTrue())
+		Ω(version).Should(Equal(uint64(7)))
+
+		version, ok = v.Delete("baz")
+		Ω(ok).Should(BeFalse())
+		Ω(version).Should(Equal(uint64(7)))
+	})
+	It("should_get_latest_value", func() {
+		value, ok := v.Get("foo")
+		Ω(ok).Should(BeTrue())
+		Ω(value).Should(Equal(3))
+
+		_, ok = v.Get("foobar")
+		Ω(ok).Should(BeFalse())
+		Ω(v.Len()).Should(Equal(2))
+	})
+	It("should_get_value_at_version", func() {
+		_, ok := v.GetAt("foo", 0)
+		Ω(ok).Should(BeFalse())
+
+		value, ok := v.GetAt("foo", 2)
+		Ω(ok).Should(BeTrue())
+		Ω(value).Should(Equal(1))
+
+		value, ok = v.GetAt("foobar", 3)
+		Ω(ok).Should(BeTrue())
+		Ω(value).Should(Equal(2))
+
+		_, ok = v.GetAt("foobar", 4)
+		Ω(ok).Should(BeFalse())
+	})
+	It("should_insert_deleted_key", func() {
+		v.Insert("foobar", 6)
+		Ω(v.Len()).Should(Equal(3))
+
+		value, ok := v.Get("foobar")
+		Ω(ok).Should(BeTrue())
+		Ω(value).Should(Equal(6))
+
+		_, ok = v.GetAt("foobar", 5)
+		Ω(ok).Should(BeFalse())
+	})
+	It("should_walk_prefix_at_version", func() {
+		walk := func(version uint64) map[string]interface{} {
+			ret := map[string]interface{}{}
+			v.WalkPrefixAt("foo", version, func(key string, value interface{}) bool {
+				ret[key] = value
+				return false
+			})
+			return ret
+		}
+
+		Ω(walk(0)).Should(BeEmpty())
+		Ω(walk(2)).Should(Equal(map[string]interface{}{"foo": 1, "foobar": 2}))
+		Ω(walk(3)).Should(Equal(map[string]interface{}{"foo": 3, "foobar": 2}))
+		Ω(walk(5)).Should(Equal(map[string]interface{}{"foo": 3}))
+
+		var keys []string
+		v.WalkPrefix("", func(key string, _ interface{}) bool {
+			keys = append(keys, key)
+			return false
+		})
+		Ω(keys).Should(Equal([]string{"bar", "foo"}))
+	})
+	It("should_compact_history", func() {
+		Ω(v.Compact(3)).Should(Equal(1))
+
+		value, ok := v.GetAt("foo", 3)
+		Ω(ok).Should(BeTrue())
+		Ω(value).Should(Equal(3))
+
+		// only keys unchanged since are found before the compaction
+		_, ok = v.GetAt("foo", 1)
+		Ω(ok).Should(BeFalse())
+		value, ok = v.GetAt("foobar", 3)
+		Ω(ok).Should(BeTrue())
+		Ω(value).Should(Equal(2))
+
+		Ω(v.Compact(5)).Should(Equal(2))
+		_, ok = v.GetAt("foobar", 3)
+		Ω(ok).Should(BeFalse())
+
+		value, ok = v.Get("bar")
+		Ω(ok).Should(BeTrue())
+		Ω(value).Should(Equal(5))
+		Ω(v.Len()).Should(Equal(2))
+		Ω(v.Compact(5)).Should(BeZero())
+	})
+})
@@ -2,10 +2,53 @@ package gorax
 
 import "sort"
 
+const (
+	// linearChildren is the maximum number of children of a branching node which are searched linearly.
+	linearChildren = 16
+	// indexedChildren is the maximum number of children of a branching node which are found by an index, the children
+	// of larger nodes are stored in a direct array.
+	indexedChildren = 48
+)
+
+// node is a node of the radix tree. The edge labels to the children are stored in key, either as a single compressed
+// label to one child or as one byte per child in sorted order. Like the node types of an Adaptive Radix Tree, the
+// children of a branching node are found by a linear search (up to 16 children), an index of the child positions
+// (up to 48 children) or a direct array of all 256 possible children.
 type node struct {
 	key      string
 	children []*node
 	value    interface{}
+
+	// lookup of the children of branching nodes with many children
+	index  *[256]uint8
+	direct *[256]*node
+
+	// caches of the subtree, allocated on first use so that nodes of Trees without these features stay small
+	cache *nodeCache
+}
+
+// nodeCache holds the cached values of the subtree of a node.
+type nodeCache struct {
+	// cached aggregate of the subtree if the Tree has a Monoid
+	aggregate  interface{}
+	aggregated bool
+
+	// cached hash of the subtree if the Tree is configured WithHash
+	hash   []byte
+	hashed bool
+
+	// cached number of keys in the subtree if the Tree was sampled by RandomKey
+	count   int
+	counted bool
+}
+
+// caches returns the caches of a node, allocating them on first use.
+func (n *node) caches() *nodeCache {
+	if n.cache == nil {
+		n.cache = &nodeCache{}
+	}
+
+	return n.cache
 }
 
 func (n node) isCompressed() bool {
@@ -21,43 +64,67 @@ func (n node) isLeaf() bool {
 }
 
 func (n *node) getValue() interface{} {
-	if n.isKey() {
-		if _, isNil := n.value.(Nil); isNil {
-			return nil
-		}
+	value := n.value
+	if e, ok := value.(expiring); ok {
+		value = e.value
+	}
+	if n, ok := value.(normalized); ok {
+		value = n.value
+	}
+	if _, isNil := value.(Nil); isNil {
+		return nil
 	}
 
-	return n.value
+	return value
 }
 
-func (n *node) getKeysWithPrefix(prefix string) []string {
-	if n.isCompressed() {
-		return []string{prefix + n.key}
-	} else {
-		ret := make([]string, len(n.key))
-		for i, key := range n.key {
-			ret[i] = prefix + string(key)
+// original returns the original key of a node if it differs from its normalized key in the Tree.
+func (n *node) original() (string, bool) {
+	value := n.value
+	if e, ok := value.(expiring); ok {
+		value = e.value
+	}
+	if n, ok := value.(normalized); ok {
+		return n.key, true
+	}
+
+	return "", false
+}
+
+// child returns the child of a branching node for a byte or nil if there is none.
+func (n *node) child(c byte) *node {
+	switch {
+	case n.direct != nil:
+		return n.direct[c]
+	case n.index != nil:
+		if i := n.index[c]; i != 0 {
+			return n.children[i-1]
 		}
+		return nil
+	}
 
-		return ret
+	for i := 0; i < len(n.key) && n.key[i] <= c; i++ {
+		if n.key[i] == c {
+			return n.children[i]
+		}
 	}
-}
 
-func (n *node) getChildren() []*node {
-	return n.children
+	return nil
 }
 
-func (n *node) addChild(key string, child *node) {
+func (n *node) addChild(a *arena, key string, child *node) {
 	idx := sort.Search(len(n.key), func(i int) bool { return n.key[i] >= key[0] })
 	if idx == len(n.key) {
-		n.key = n.key + key
+		n.key = a.label(n.key, key)
 		n.children = append(n.children, child)
 	} else {
-		n.key = n.key[:idx] + key + n.key[idx:]
+		n.key = a.label(n.key[:idx], key, n.key[idx:])
 
 		n.children = append(n.children[:idx+1], n.children[idx:]...)
 		n.children[idx] = child
 	}
+
+	n.adapt(idx)
 }
 
 func (n *node) addCompressedChild(key string, child *node) {
@@ -65,7 +132,7 @@ func (n *node) addCompressedChild(key string, child *node) {
 	n.children = []*node{child}
 }
 
-func (n *node) removeChild(child *node) {
+func (n *node) removeChild(a *arena, child *node) {
 	if n.isCompressed() {
 		n.key = ""
 		n.children = nil
@@ -74,14 +141,74 @@ func (n *node) removeChild(child *node) {
 
 	for idx := range n.children {
 		if n.children[idx] == child {
+			switch {
+			case n.direct != nil:
+				n.direct[n.key[idx]] = nil
+			case n.index != nil:
+				n.index[n.key[idx]] = 0
+			}
+
 			if idx+1 < len(n.children) {
 				n.children = append(n.children[:idx], n.children[idx+1:]...)
-				n.key = n.key[:idx] + n.key[idx+1:]
+				n.key = a.label(n.key[:idx], n.key[idx+1:])
 			} else {
 				n.children = n.children[:idx]
 				n.key = n.key[:idx]
 			}
+
+			n.adapt(idx)
+			break
+		}
+	}
+}
+
+// replaceChild replaces a child of the node.
+func (n *node) replaceChild(old, child *node) {
+	for idx := range n.children {
+		if n.children[idx] == old {
+			n.children[idx] = child
+			if n.direct != nil {
+				n.direct[n.key[idx]] = child
+			}
 			break
 		}
 	}
 }
+
+// removeChildren removes all children of the node.
+func (n *node) removeChildren() {
+	n.key = ""
+	n.children = nil
+	n.index = nil
+	n.direct = nil
+}
+
+// adapt grows or shrinks the lookup of a branching node to its number of children and updates the lookup of the
+// children from the position 'from' on.
+func (n *node) adapt(from int) {
+	switch {
+	case len(n.children) > indexedChildren:
+		if n.direct == nil {
+			n.index = nil
+			n.direct = &[256]*node{}
+			from = 0
+		}
+
+		for i := from; i < len(n.children); i++ {
+			n.direct[n.key[i]] = n.children[i]
+		}
+	case len(n.children) > linearChildren:
+		if n.index == nil {
+			n.direct = nil
+			n.index = &[256]uint8{}
+			from = 0
+		}
+
+		for i := from; i < len(n.children); i++ {
+			n.index[n.key[i]] = uint8(i + 1)
+		}
+	default:
+		n.index = nil
+		n.direct = nil
+	}
+}
@@ -0,0 +1,67 @@
+package gorax
+
+import (
+	"strings"
+	"unicode"
+)
+
+// CaseFold is a normalizer for WithNormalizer which makes keys case-insensitive. Keys which are equal under Unicode
+// simple case folding, like with strings.EqualFold, are normalized to the same lower case key.
+func CaseFold(key string) string {
+	return strings.Map(func(r rune) rune {
+		// use the smallest rune of the case folding orbit to map e.g. 'K', 'k' and the Kelvin sign to the same rune
+		smallest := r
+		for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+			if f < smallest {
+				smallest = f
+			}
+		}
+
+		return unicode.ToLower(smallest)
+	}, key)
+}
+
+// normalized wraps the value of a key whose original key differs from its normalized key in the Tree.
+type normalized struct {
+	key   string
+	value interface{}
+}
+
+// normalizeKey returns the key normalized by the normalizer of the Tree.
+func (t *Tree) normalizeKey(key string) string {
+	if t.normalizer == nil {
+		return key
+	}
+
+	return t.normalizer(key)
+}
+
+// normalize returns the normalized key and the value to insert for it, which keeps the original key if it differs.
+func (t *Tree) normalize(key string, value interface{}) (string, interface{}) {
+	normalizedKey := t.normalizeKey(key)
+	if normalizedKey == key {
+		return key, value
+	}
+
+	// the original key is wrapped inside the expiring value which is checked on its own by Sweep
+	if e, ok := value.(expiring); ok {
+		e.value = normalized{key: key, value: e.value}
+		return normalizedKey, e
+	}
+
+	return normalizedKey, normalized{key: key, value: value}
+}
+
+// originalKeys returns a function for walkPrefix which passes the original keys and the values of the nodes to fn.
+func originalKeys(fn WalkBytesFn) func([]byte, *node) bool {
+	var buf []byte
+
+	return func(key []byte, n *node) bool {
+		if original, ok := n.original(); ok {
+			buf = append(buf[:0], original...)
+			key = buf
+		}
+
+		return fn(key, n.getValue())
+	}
+}
@@ -0,0 +1,144 @@
+package gorax_test
+
+import (
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/snorwin/gorax"
+)
+
+var _ = Describe("Tree", func() {
+	Context("Unicode", func() {
+		It("should_walk_non_ascii_keys", func() {
+			expected := map[string]interface{}{
+				"café":    1,
+				"cafè":    2,
+				"caf\xff": 3,
+				"日本":      4,
+				"日本語":     5,
+			}
+
+			t := gorax.FromMap(expected)
+			Ω(t.ToMap()).Should(Equal(expected))
+
+			var keys []string
+			t.WalkPrefix("caf", func(key string, _ interface{}) bool {
+				keys = append(keys, key)
+
+				return false
+			})
+			Ω(keys).Should(Equal([]string{"cafè", "café", "caf\xff"}))
+
+			key, value, ok := t.LongestPrefix("日本語版")
+			Ω(ok).Should(BeTrue())
+			Ω(key).Should(Equal("日本語"))
+			Ω(value).Should(Equal(5))
+		})
+	})
+	Context("CaseFold", func() {
+		It("should_fold_case", func() {
+			Ω(gorax.CaseFold("Hello WORLD")).Should(Equal("hello world"))
+			Ω(gorax.CaseFold("ÄÖÜ")).Should(Equal("äöü"))
+			Ω(gorax.CaseFold("K")).Should(Equal("k"))
+		})
+	})
+	Context("WithNormalizer", func() {
+		var (
+			t *gorax.Tree
+		)
+		BeforeEach(func() {
+			t = gorax.FromMap(map[string]interface{}{
+				"Apple":        1,
+				"apple pie":    2,
+				"Äpfel":        3,
+				"BANANA":       4,
+				"banana split": 5,
+			}, gorax.WithNormalizer(gorax.CaseFold))
+		})
+		It("should_get_case_insensitive", func() {
+			value, ok := t.Get("APPLE")
+			Ω(ok).Should(BeTrue())
+			Ω(value).Should(Equal(1))
+
+			value, ok = t.Get("äPFEL")
+			Ω(ok).Should(BeTrue())
+			Ω(value).Should(Equal(3))
+			Ω(t.Len()).Should(Equal(5))
+		})
+		It("should_walk_original_keys", func() {
+			var keys []string
+			t.Walk(func(key string, _ interface{}) bool {
+				keys = append(keys, key)
+
+				return false
+			})
+			Ω(keys).Should(Equal([]string{"Apple", "apple pie", "BANANA", "banana split", "Äpfel"}))
+
+			keys = nil
+			t.WalkPrefixBytes([]byte("Ban"), func(key []byte, _ interface{}) bool {
+				keys = append(keys, string(key))
+
+				return false
+			})
+			Ω(keys).Should(Equal([]string{"BANANA", "banana split"}))
+		})
+		It("should_find_longest_prefix_with_original_key", func() {
+			key, value, ok := t.LongestPrefix("BANANAS")
+			Ω(ok).Should(BeTrue())
+			Ω(key).Should(Equal("BANANA"))
+			Ω(value).Should(Equal(4))
+
+			prefix, _, ok := t.LongestPrefixBytes([]byte("bananas"))
+			Ω(ok).Should(BeTrue())
+			Ω(string(prefix)).Should(Equal("BANANA"))
+		})
+		It("should_keep_last_inserted_original_key", func() {
+			Ω(t.Insert("APPLE", 6)).Should(BeFalse())
+
+			key, value, ok := t.Minimum()
+			Ω(ok).Should(BeTrue())
+			Ω(key).Should(Equal("APPLE"))
+			Ω(value).Should(Equal(6))
+		})
+		It("should_delete_case_insensitive", func() {
+			value, ok := t.Delete("banana")
+			Ω(ok).Should(BeTrue())
+			Ω(value).Should(Equal(4))
+
+			Ω(t.DeletePrefix("APP")).Should(Equal(2))
+			Ω(t.ToMap()).Should(Equal(map[string]interface{}{"Äpfel": 3, "banana split": 5}))
+		})
+		It("should_evict_original_key", func() {
+			now := time.Now()
+			var evicted []string
+			t := gorax.New(
+				gorax.WithNormalizer(gorax.CaseFold),
+				gorax.WithClock(func() time.Time { return now }),
+				gorax.WithEvictFn(func(key string, _ interface{}) {
+					evicted = append(evicted, key)
+				}),
+			)
+			t.InsertWithTTL("Expiring", 1, time.Minute)
+
+			value, ok := t.Get("EXPIRING")
+			Ω(ok).Should(BeTrue())
+			Ω(value).Should(Equal(1))
+
+			now = now.Add(time.Hour)
+			Ω(t.Len()).Should(BeZero())
+			Ω(evicted).Should(Equal([]string{"Expiring"}))
+		})
+		It("should_use_pluggable_normalizer", func() {
+			t := gorax.New(gorax.WithNormalizer(strings.TrimSpace))
+			t.Insert(" foo ", 1)
+
+			value, ok := t.Get("foo")
+			Ω(ok).Should(BeTrue())
+			Ω(value).Should(Equal(1))
+			Ω(t.ToMap()).Should(Equal(map[string]interface{}{" foo ": 1}))
+		})
+	})
+})
@@ -0,0 +1,90 @@
+package gorax
+
+import "time"
+
+// Option configures a Tree.
+type Option func(*Tree)
+
+// WithSeparator configures a Tree for keys made of segments delimited by a separator (e.g. '/' for paths).
+// LongestPrefix, WalkPrefix, DeletePrefix and WalkPath then only match on segment boundaries, i.e. the key '/api/user'
+// is not a prefix of '/api/users', but of '/api/user/1'.
+func WithSeparator(separator byte) Option {
+	return func(t *Tree) {
+		t.separator = separator
+	}
+}
+
+// WithAggregate configures a Tree to cache the aggregate of every subtree using the Monoid. The aggregates are kept up
+// to date by Insert, Delete and DeletePrefix and are returned by Aggregate.
+func WithAggregate(monoid Monoid) Option {
+	return func(t *Tree) {
+		t.monoid = monoid
+	}
+}
+
+// WithClock configures the clock used to expire entries inserted with InsertWithTTL, defaults to time.Now.
+func WithClock(now func() time.Time) Option {
+	return func(t *Tree) {
+		t.clock = now
+	}
+}
+
+// WithEvictFn configures a function called for every entry removed by the Tree itself, e.g. because it expired.
+func WithEvictFn(fn EvictFn) Option {
+	return func(t *Tree) {
+		t.evict = fn
+	}
+}
+
+// WithCapacity limits the number of keys in a Tree. If an Insert exceeds the capacity, keys are evicted according to
+// the EvictionPolicy and reported to the EvictFn. Get and LongestPrefix hits count as use of a key.
+func WithCapacity(capacity int) Option {
+	return WithPrefixCapacity("", capacity)
+}
+
+// WithPrefixCapacity limits the number of keys under a prefix, e.g. the keys of a tenant. It can be used multiple
+// times and together with WithCapacity.
+func WithPrefixCapacity(prefix string, capacity int) Option {
+	return func(t *Tree) {
+		t.bounds = append(t.bounds, &bound{prefix: prefix, capacity: capacity})
+	}
+}
+
+// WithEvictionPolicy configures which keys are evicted from a Tree with a capacity, defaults to LRU.
+func WithEvictionPolicy(policy EvictionPolicy) Option {
+	return func(t *Tree) {
+		t.policy = policy
+	}
+}
+
+// WithArena configures a Tree to allocate its nodes in slabs and its edge labels in large chunks, which reduces the
+// number of allocations of bulk inserts. Nodes removed by Delete and DeletePrefix are reused, Reset keeps all slabs.
+// Label chunks are not reused by Reset, as their labels may still be referenced by keys returned from the Tree.
+func WithArena() Option {
+	return func(t *Tree) {
+		t.arena.enabled = true
+	}
+}
+
+// WithNormalizer configures a Tree to normalize all keys with a function, e.g. CaseFold or the Unicode NFC form of
+// golang.org/x/text/unicode/norm. The function must be idempotent. Keys are inserted, looked up, deleted and walked by
+// their normalized form, keys returned by the Tree are the original keys as last inserted. List, Children and the
+// prefixes of WithPrefixCapacity work on the normalized keys.
+func WithNormalizer(normalizer func(key string) string) Option {
+	return func(t *Tree) {
+		t.normalizer = normalizer
+	}
+}
+
+// WithHash configures a Tree to maintain a SHA-256 hash of every subtree over its edge labels, child hashes and the
+// values encoded by the Encoder, defaults to EncodeValue. The hashes are invalidated by every mutation and recomputed
+// on demand by RootHash and Prove. Without an Encoder, Insert panics for values of types not supported by EncodeValue.
+func WithHash(encode Encoder) Option {
+	return func(t *Tree) {
+		t.checkValues = encode == nil
+		if encode == nil {
+			encode = EncodeValue
+		}
+		t.encode = encode
+	}
+}
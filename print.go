@@ -0,0 +1,107 @@
+package gorax
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// PrintOptions are the parameters of Print.
+type PrintOptions struct {
+	// Prefix limits the output to the subtree under the prefix, which starts with its quoted key.
+	Prefix string
+
+	// MaxDepth is the maximum number of edges printed below the root of the output, deeper subtrees are elided as
+	// '...'. Zero means unlimited.
+	MaxDepth int
+
+	// FormatValue formats the values of keys, defaults to the default format of the fmt package.
+	FormatValue func(value interface{}) string
+}
+
+// String returns the rendering of the Tree by Print.
+func (t *Tree) String() string {
+	var b strings.Builder
+	_ = t.Print(&b, PrintOptions{})
+
+	return b.String()
+}
+
+// Print writes an indented rendering of the nodes of the Tree, like raxShow of rax. Compressed edges are printed in
+// quotes and followed by their child, branching nodes print the bytes of their edges in brackets and each child on
+// its own line after the edge byte in parentheses. Keys are followed by '=' and their value, e.g.
+//
+//	"fo" -> [ox]
+//	        `-(o) "bar"=1 -> []=2
+//	        `-(x) []=3
+func (t *Tree) Print(w io.Writer, opts PrintOptions) error {
+	t.expire()
+
+	if opts.FormatValue == nil {
+		opts.FormatValue = func(value interface{}) string {
+			return fmt.Sprint(value)
+		}
+	}
+
+	var b strings.Builder
+
+	start := &t.root
+	if opts.Prefix != "" {
+		var key string
+		start, key = t.subtree(t.normalizeKey(opts.Prefix))
+		if start == nil {
+			return nil
+		}
+		b.WriteString(strconv.Quote(key) + ": ")
+	}
+
+	printNode(&b, start, utf8.RuneCountInString(b.String()), 0, opts)
+	b.WriteString("\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// printNode prints a node starting at the column of the current line, children of branching nodes are indented to it.
+func printNode(b *strings.Builder, n *node, column, depth int, opts PrintOptions) {
+	var s string
+	switch {
+	case n.isCompressed():
+		s = strconv.Quote(n.key)
+	default:
+		s = "[" + escape(n.key) + "]"
+	}
+	if n.isKey() {
+		s += "=" + opts.FormatValue(n.getValue())
+	}
+	b.WriteString(s)
+
+	if len(n.children) == 0 {
+		return
+	}
+	if opts.MaxDepth > 0 && depth >= opts.MaxDepth {
+		b.WriteString(" -> ...")
+		return
+	}
+
+	if n.isCompressed() {
+		b.WriteString(" -> ")
+		printNode(b, n.children[0], column+utf8.RuneCountInString(s)+len(" -> "), depth+1, opts)
+		return
+	}
+
+	for i, child := range n.children {
+		edge := "`-(" + escape(n.key[i:i+1]) + ") "
+		b.WriteString("\n" + strings.Repeat(" ", column) + edge)
+		printNode(b, child, column+utf8.RuneCountInString(edge), depth+1, opts)
+	}
+}
+
+// escape returns a string with the escapes of a quoted string, but without quotes.
+func escape(s string) string {
+	q := strconv.Quote(s)
+
+	return q[1 : len(q)-1]
+}
@@ -0,0 +1,83 @@
+package gorax_test
+
+import (
+	"fmt"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/snorwin/gorax"
+)
+
+var _ = Describe("Tree", func() {
+	Context("Print", func() {
+		var (
+			t *gorax.Tree
+		)
+		BeforeEach(func() {
+			t = gorax.FromMap(map[string]interface{}{
+				"foo":    1,
+				"foobar": 2,
+				"fox":    3,
+				"bar":    nil,
+			})
+		})
+		print := func(opts gorax.PrintOptions) string {
+			var b strings.Builder
+			Ω(t.Print(&b, opts)).Should(Succeed())
+			return b.String()
+		}
+		It("should_print_empty_tree", func() {
+			Ω(gorax.New().String()).Should(Equal("[]\n"))
+		})
+		It("should_print_tree", func() {
+			Ω(t.String()).Should(Equal(strings.Join([]string{
+				"[bf]",
+				"`-(b) \"ar\" -> []=<nil>",
+				"`-(f) [o]",
+				"      `-(o) [ox]",
+				"            `-(o) \"bar\"=1 -> []=2",
+				"            `-(x) []=3",
+				"",
+			}, "\n")))
+		})
+		It("should_limit_depth", func() {
+			Ω(print(gorax.PrintOptions{MaxDepth: 1})).Should(Equal(strings.Join([]string{
+				"[bf]",
+				"`-(b) \"ar\" -> ...",
+				"`-(f) [o] -> ...",
+				"",
+			}, "\n")))
+		})
+		It("should_print_subtree", func() {
+			Ω(print(gorax.PrintOptions{Prefix: "fo"})).Should(Equal(strings.Join([]string{
+				"\"fo\": [ox]",
+				"      `-(o) \"bar\"=1 -> []=2",
+				"      `-(x) []=3",
+				"",
+			}, "\n")))
+			Ω(print(gorax.PrintOptions{Prefix: "foob"})).Should(Equal("\"foobar\": []=2\n"))
+			Ω(print(gorax.PrintOptions{Prefix: "jin"})).Should(BeEmpty())
+		})
+		It("should_format_values", func() {
+			Ω(print(gorax.PrintOptions{
+				Prefix: "foo",
+				FormatValue: func(value interface{}) string {
+					return fmt.Sprintf("%03d", value)
+				},
+			})).Should(Equal("\"foo\": \"bar\"=001 -> []=002\n"))
+		})
+		It("should_escape_labels", func() {
+			t := gorax.New()
+			t.Insert("a\"b", 1)
+			t.Insert("\n", 2)
+			Ω(t.String()).Should(Equal(strings.Join([]string{
+				"[\\na]",
+				"`-(\\n) []=2",
+				"`-(a) \"\\\"b\" -> []=1",
+				"",
+			}, "\n")))
+		})
+	})
+})
@@ -0,0 +1,105 @@
+package gorax
+
+import "math/rand"
+
+// RandomKey returns a key of the Tree chosen uniformly at random using the rng, its value and if the Tree has any key.
+// It descends the Tree once, choosing every child with the probability of the number of keys in its subtree, which
+// are cached in the nodes and invalidated by all subsequent mutations.
+func (t *Tree) RandomKey(rng *rand.Rand) (string, interface{}, bool) {
+	return t.RandomKeyPrefix("", rng)
+}
+
+// RandomKeyPrefix is like RandomKey, but only chooses among the keys under a prefix. If the Tree has a separator, only
+// the prefix itself and the keys of the segments below it are chosen.
+func (t *Tree) RandomKeyPrefix(prefix string, rng *rand.Rand) (string, interface{}, bool) {
+	t.expire()
+
+	t.counting = true
+
+	prefix = t.normalizeKey(prefix)
+	if t.isSegmentPrefix(prefix) {
+		current, key := t.subtree(prefix)
+		return t.random(current, key, rng)
+	}
+
+	// choose between the prefix itself and the keys of the segments below it
+	current, key := t.subtree(t.segmentPrefix(prefix))
+	total := t.count(current)
+	exact := t.lookup(prefix)
+	if exact != nil {
+		total += 1
+	}
+	if total == 0 {
+		return "", nil, false
+	}
+
+	if exact != nil && rng.Intn(total) == 0 {
+		if original, ok := exact.original(); ok {
+			prefix = original
+		}
+		return prefix, exact.getValue(), true
+	}
+
+	return t.random(current, key, rng)
+}
+
+// random returns a key chosen uniformly at random from the subtree of a node with the key.
+func (t *Tree) random(n *node, key string, rng *rand.Rand) (string, interface{}, bool) {
+	if t.count(n) == 0 {
+		return "", nil, false
+	}
+
+	r := rng.Intn(t.count(n))
+
+	buf := []byte(key)
+	for {
+		if n.isKey() {
+			if r == 0 {
+				break
+			}
+			r -= 1
+		}
+
+		for i, child := range n.children {
+			if c := t.count(child); r >= c {
+				r -= c
+				continue
+			}
+
+			if n.isCompressed() {
+				buf = append(buf, n.key...)
+			} else {
+				buf = append(buf, n.key[i])
+			}
+			n = child
+			break
+		}
+	}
+
+	if original, ok := n.original(); ok {
+		return original, n.getValue(), true
+	}
+
+	return string(buf), n.getValue(), true
+}
+
+// count returns the number of keys in the subtree of a node and updates it first if it was invalidated.
+func (t *Tree) count(n *node) int {
+	if n == nil {
+		return 0
+	}
+
+	c := n.caches()
+	if !c.counted {
+		c.count = 0
+		if n.isKey() {
+			c.count = 1
+		}
+		for _, child := range n.children {
+			c.count += t.count(child)
+		}
+		c.counted = true
+	}
+
+	return c.count
+}
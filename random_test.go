@@ -0,0 +1,115 @@
+package gorax_test
+
+import (
+	"fmt"
+	"math/rand"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/snorwin/gorax"
+)
+
+var _ = Describe("Tree", func() {
+	Context("RandomKey", func() {
+		var (
+			rng *rand.Rand
+		)
+		BeforeEach(func() {
+			rng = rand.New(rand.NewSource(1))
+		})
+
+		// sample counts how often every key is chosen by n calls of fn
+		sample := func(n int, fn func() (string, interface{}, bool)) map[string]int {
+			ret := map[string]int{}
+			for i := 0; i < n; i++ {
+				key, _, ok := fn()
+				Ω(ok).Should(BeTrue())
+				ret[key] += 1
+			}
+			return ret
+		}
+
+		It("should_not_fail_if_empty", func() {
+			t := gorax.New()
+			_, _, ok := t.RandomKey(rng)
+			Ω(ok).Should(BeFalse())
+
+			t.Insert("foo", 1)
+			_, _, ok = t.RandomKeyPrefix("bar", rng)
+			Ω(ok).Should(BeFalse())
+		})
+		It("should_return_key_and_value", func() {
+			t := gorax.New(gorax.WithNormalizer(gorax.CaseFold))
+			t.Insert("Foo", 1)
+
+			key, value, ok := t.RandomKey(rng)
+			Ω(ok).Should(BeTrue())
+			Ω(key).Should(Equal("Foo"))
+			Ω(value).Should(Equal(1))
+		})
+		It("should_sample_uniformly", func() {
+			// a shallow key next to a deep subtree with many keys
+			t := gorax.New()
+			t.Insert("a", nil)
+			for i := 0; i < 99; i++ {
+				t.Insert(fmt.Sprintf("b/%02d", i), nil)
+			}
+
+			n := 100000
+			counts := sample(n, func() (string, interface{}, bool) { return t.RandomKey(rng) })
+			Ω(counts).Should(HaveLen(100))
+
+			// chi-squared test with 99 degrees of freedom, the critical value for p = 0.001 is 148.2
+			var chi2 float64
+			expected := float64(n) / 100
+			for _, count := range counts {
+				chi2 += (float64(count) - expected) * (float64(count) - expected) / expected
+			}
+			Ω(chi2).Should(BeNumerically("<", 148.2))
+		})
+		It("should_sample_under_prefix", func() {
+			t := gorax.New()
+			for _, key := range []string{"foo", "foobar", "foobaz", "fox", "bar"} {
+				t.Insert(key, nil)
+			}
+
+			counts := sample(1000, func() (string, interface{}, bool) { return t.RandomKeyPrefix("foob", rng) })
+			Ω(counts).Should(HaveLen(2))
+			Ω(counts).Should(HaveKey("foobar"))
+			Ω(counts).Should(HaveKey("foobaz"))
+
+			counts = sample(1000, func() (string, interface{}, bool) { return t.RandomKeyPrefix("fo", rng) })
+			Ω(counts).Should(HaveLen(4))
+		})
+		It("should_sample_segments_with_separator", func() {
+			t := gorax.New(gorax.WithSeparator('/'))
+			for _, key := range []string{"/api", "/api/user", "/api/user/1", "/apix"} {
+				t.Insert(key, nil)
+			}
+
+			counts := sample(3000, func() (string, interface{}, bool) { return t.RandomKeyPrefix("/api", rng) })
+			Ω(counts).Should(HaveLen(3))
+			for _, count := range counts {
+				Ω(count).Should(BeNumerically("~", 1000, 150))
+			}
+		})
+		It("should_update_counts_after_mutations", func() {
+			t := gorax.New()
+			t.Insert("foo", nil)
+			t.Insert("foobar", nil)
+			Ω(sample(100, func() (string, interface{}, bool) { return t.RandomKey(rng) })).Should(HaveLen(2))
+
+			t.Insert("fo", nil)
+			t.Insert("bar", nil)
+			Ω(sample(1000, func() (string, interface{}, bool) { return t.RandomKey(rng) })).Should(HaveLen(4))
+
+			t.Delete("foo")
+			t.DeletePrefix("b")
+			counts := sample(1000, func() (string, interface{}, bool) { return t.RandomKey(rng) })
+			Ω(counts).Should(HaveLen(2))
+			Ω(counts).Should(HaveKey("fo"))
+			Ω(counts).Should(HaveKey("foobar"))
+		})
+	})
+})
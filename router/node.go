@@ -0,0 +1,103 @@
+package router
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/snorwin/gorax"
+)
+
+// node is a path segment of the routing tree.
+type node struct {
+	// static children indexed by their segment
+	static *gorax.Tree
+
+	// parameter child and its name
+	param     *node
+	paramName string
+
+	// catch-all child and its name
+	catchAll     *node
+	catchAllName string
+
+	// path the handlers were registered with
+	path     string
+	handlers map[string]http.Handler
+}
+
+func newNode() *node {
+	return &node{
+		static:   gorax.New(),
+		handlers: map[string]http.Handler{},
+	}
+}
+
+func (n *node) addStatic(segment string) *node {
+	if child, ok := n.static.Get(segment); ok {
+		return child.(*node)
+	}
+
+	child := newNode()
+	n.static.Insert(segment, child)
+
+	return child
+}
+
+func (n *node) addParam(path, name string) *node {
+	if name == "" {
+		panic(fmt.Sprintf("router: empty parameter name in path %q", path))
+	}
+
+	if n.param == nil {
+		n.param = newNode()
+		n.paramName = name
+	} else if n.paramName != name {
+		panic(fmt.Sprintf("router: parameter ':%s' in path %q conflicts with existing parameter ':%s'", name, path, n.paramName))
+	}
+
+	return n.param
+}
+
+func (n *node) addCatchAll(path, name string) *node {
+	if name == "" {
+		panic(fmt.Sprintf("router: empty catch-all name in path %q", path))
+	}
+
+	if n.catchAll == nil {
+		n.catchAll = newNode()
+		n.catchAllName = name
+	} else if n.catchAllName != name {
+		panic(fmt.Sprintf("router: catch-all '*%s' in path %q conflicts with existing catch-all '*%s'", name, path, n.catchAllName))
+	}
+
+	return n.catchAll
+}
+
+// match calls fn for every node with handlers matching the segments, ordered by priority (static, parameter,
+// catch-all). Returns 'true' if fn terminated the matching.
+func (n *node) match(segments []string, params Params, fn func(*node, Params) bool) bool {
+	if len(segments) == 0 {
+		return len(n.handlers) > 0 && fn(n, params)
+	}
+
+	segment, rest := segments[0], segments[1:]
+
+	if child, ok := n.static.Get(segment); ok {
+		if child.(*node).match(rest, params, fn) {
+			return true
+		}
+	}
+
+	if n.param != nil && segment != "" {
+		if n.param.match(rest, append(params[:len(params):len(params)], Param{Key: n.paramName, Value: segment}), fn) {
+			return true
+		}
+	}
+
+	if n.catchAll != nil && len(n.catchAll.handlers) > 0 {
+		return fn(n.catchAll, append(params[:len(params):len(params)], Param{Key: n.catchAllName, Value: strings.Join(segments, "/")}))
+	}
+
+	return false
+}
@@ -0,0 +1,44 @@
+package router
+
+import (
+	"context"
+	"net/http"
+)
+
+// Param is a named parameter or catch-all of a matched route.
+type Param struct {
+	Key   string
+	Value string
+}
+
+// Params are the parameters of a matched route in the order of the path.
+type Params []Param
+
+// Get returns the value of the first parameter with the given name or an empty string.
+func (ps Params) Get(name string) string {
+	for _, p := range ps {
+		if p.Key == name {
+			return p.Value
+		}
+	}
+
+	return ""
+}
+
+type paramsKey struct{}
+
+// ParamsFromContext returns the parameters stored in the context of a routed request.
+func ParamsFromContext(ctx context.Context) Params {
+	params, _ := ctx.Value(paramsKey{}).(Params)
+
+	return params
+}
+
+// ParamsFromRequest returns the parameters of a routed request.
+func ParamsFromRequest(r *http.Request) Params {
+	return ParamsFromContext(r.Context())
+}
+
+func withParams(ctx context.Context, params Params) context.Context {
+	return context.WithValue(ctx, paramsKey{}, params)
+}
@@ -0,0 +1,139 @@
+// Package router implements a method-aware HTTP request router on top of the gorax radix tree.
+//
+// Routes are slash separated paths whose segments are either static, a named parameter (':name') matching exactly
+// one segment, or a catch-all (`*name`) matching the rest of the path. Static segments take precedence over
+// parameters and parameters over catch-alls:
+//
+//	r := router.New()
+//	r.HandleFunc(http.MethodGet, "/users/new", newUser)
+//	r.HandleFunc(http.MethodGet, "/users/:id", getUser)
+//	r.HandleFunc(http.MethodGet, "/users/:id/files/*path", getFile)
+package router
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// Router is a http.Handler dispatching requests to the handler of the matching route.
+type Router struct {
+	root *node
+
+	// NotFound is called if no route matches the path, defaults to http.NotFoundHandler.
+	NotFound http.Handler
+
+	// MethodNotAllowed is called if routes match the path, but none of them for the request method. The 'Allow'
+	// header is already set when it is called. Defaults to a plain '405 Method Not Allowed' response.
+	MethodNotAllowed http.Handler
+}
+
+// New returns an empty Router.
+func New() *Router {
+	return &Router{
+		root: newNode(),
+	}
+}
+
+// Handle registers the handler for a method and path. Handle panics if the path is invalid or conflicts with an
+// already registered route.
+func (r *Router) Handle(method, path string, handler http.Handler) {
+	if method == "" {
+		panic("router: empty method")
+	}
+	if handler == nil {
+		panic("router: nil handler")
+	}
+	if !strings.HasPrefix(path, "/") {
+		panic(fmt.Sprintf("router: path %q must begin with '/'", path))
+	}
+
+	segments := strings.Split(path[1:], "/")
+
+	current := r.root
+	for i, segment := range segments {
+		switch {
+		case strings.HasPrefix(segment, ":"):
+			current = current.addParam(path, segment[1:])
+		case strings.HasPrefix(segment, "*"):
+			if i != len(segments)-1 {
+				panic(fmt.Sprintf("router: catch-all in path %q must be the last segment", path))
+			}
+			current = current.addCatchAll(path, segment[1:])
+		default:
+			current = current.addStatic(segment)
+		}
+	}
+
+	if _, exists := current.handlers[method]; exists {
+		panic(fmt.Sprintf("router: route %s %q conflicts with existing route %s %q", method, path, method, current.path))
+	}
+
+	current.path = path
+	current.handlers[method] = handler
+}
+
+// HandleFunc registers the handler function for a method and path.
+func (r *Router) HandleFunc(method, path string, handler func(http.ResponseWriter, *http.Request)) {
+	r.Handle(method, path, http.HandlerFunc(handler))
+}
+
+// ServeHTTP dispatches the request to the handler of the best matching route.
+func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	path := req.URL.Path
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+
+	var allowed []string
+	var handler http.Handler
+	var params Params
+	r.root.match(strings.Split(path[1:], "/"), nil, func(n *node, p Params) bool {
+		if h, ok := n.handlers[req.Method]; ok {
+			handler = h
+			params = p
+
+			return true
+		}
+
+		for method := range n.handlers {
+			allowed = append(allowed, method)
+		}
+
+		return false
+	})
+
+	switch {
+	case handler != nil:
+		if len(params) > 0 {
+			req = req.WithContext(withParams(req.Context(), params))
+		}
+		handler.ServeHTTP(w, req)
+	case len(allowed) > 0:
+		w.Header().Set("Allow", allow(allowed))
+		if r.MethodNotAllowed != nil {
+			r.MethodNotAllowed.ServeHTTP(w, req)
+		} else {
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		}
+	case r.NotFound != nil:
+		r.NotFound.ServeHTTP(w, req)
+	default:
+		http.NotFound(w, req)
+	}
+}
+
+// allow returns the sorted and deduplicated methods for the 'Allow' header.
+func allow(methods []string) string {
+	sort.Strings(methods)
+
+	ret := methods[:0]
+	for _, method := range methods {
+		if len(ret) == 0 || method != ret[len(ret)-1] {
+			ret = append(ret, method)
+		}
+	}
+
+	return strings.Join(ret, ", ")
+}
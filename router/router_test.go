@@ -0,0 +1,107 @@
+package router_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/snorwin/gorax/router"
+)
+
+// echo responds with the route name and its parameters.
+func echo(name string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, name)
+		for _, p := range router.ParamsFromRequest(r) {
+			_, _ = fmt.Fprintf(w, " %s=%s", p.Key, p.Value)
+		}
+	}
+}
+
+var _ = Describe("Router", func() {
+	var (
+		r *router.Router
+	)
+	BeforeEach(func() {
+		r = router.New()
+		r.HandleFunc(http.MethodGet, "/", echo("root"))
+		r.HandleFunc(http.MethodGet, "/users", echo("list"))
+		r.HandleFunc(http.MethodPost, "/users", echo("create"))
+		r.HandleFunc(http.MethodGet, "/users/new", echo("new"))
+		r.HandleFunc(http.MethodGet, "/users/:id", echo("get"))
+		r.HandleFunc(http.MethodDelete, "/users/:id", echo("delete"))
+		r.HandleFunc(http.MethodGet, "/users/:id/files/*path", echo("file"))
+		r.HandleFunc(http.MethodGet, "/static/*path", echo("static"))
+		r.HandleFunc(http.MethodGet, "/static/index.html", echo("index"))
+	})
+	serve := func(method, path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+
+		return w
+	}
+	Context("ServeHTTP", func() {
+		It("should_route_static_paths", func() {
+			Ω(serve(http.MethodGet, "/").Body.String()).Should(Equal("root"))
+			Ω(serve(http.MethodGet, "/users").Body.String()).Should(Equal("list"))
+			Ω(serve(http.MethodPost, "/users").Body.String()).Should(Equal("create"))
+		})
+		It("should_route_parameters", func() {
+			Ω(serve(http.MethodGet, "/users/42").Body.String()).Should(Equal("get id=42"))
+			Ω(serve(http.MethodDelete, "/users/42").Body.String()).Should(Equal("delete id=42"))
+		})
+		It("should_route_catch_all", func() {
+			Ω(serve(http.MethodGet, "/users/42/files/docs/a.txt").Body.String()).Should(Equal("file id=42 path=docs/a.txt"))
+			Ω(serve(http.MethodGet, "/static/").Body.String()).Should(Equal("static path="))
+		})
+		It("should_prefer_static_over_parameter_and_catch_all", func() {
+			Ω(serve(http.MethodGet, "/users/new").Body.String()).Should(Equal("new"))
+			Ω(serve(http.MethodGet, "/static/index.html").Body.String()).Should(Equal("index"))
+			Ω(serve(http.MethodGet, "/static/app.js").Body.String()).Should(Equal("static path=app.js"))
+		})
+		It("should_fall_back_to_parameter_if_method_does_not_match", func() {
+			Ω(serve(http.MethodDelete, "/users/new").Body.String()).Should(Equal("delete id=new"))
+		})
+		It("should_respond_not_found", func() {
+			Ω(serve(http.MethodGet, "/groups").Code).Should(Equal(http.StatusNotFound))
+			Ω(serve(http.MethodGet, "/users/42/files").Code).Should(Equal(http.StatusNotFound))
+			Ω(serve(http.MethodGet, "/users//files/a").Code).Should(Equal(http.StatusNotFound))
+		})
+		It("should_respond_method_not_allowed", func() {
+			w := serve(http.MethodPut, "/users/new")
+			Ω(w.Code).Should(Equal(http.StatusMethodNotAllowed))
+			Ω(w.Header().Get("Allow")).Should(Equal("DELETE, GET"))
+		})
+		It("should_use_custom_handlers", func() {
+			r.NotFound = echo("not found")
+			r.MethodNotAllowed = echo("not allowed")
+
+			Ω(serve(http.MethodGet, "/groups").Body.String()).Should(Equal("not found"))
+			Ω(serve(http.MethodPut, "/users").Body.String()).Should(Equal("not allowed"))
+		})
+	})
+	Context("Handle", func() {
+		It("should_panic_on_duplicate_route", func() {
+			Ω(func() { r.HandleFunc(http.MethodGet, "/users/:id", echo("")) }).Should(Panic())
+		})
+		It("should_panic_on_conflicting_parameter", func() {
+			Ω(func() { r.HandleFunc(http.MethodPut, "/users/:name", echo("")) }).Should(Panic())
+		})
+		It("should_panic_on_conflicting_catch_all", func() {
+			Ω(func() { r.HandleFunc(http.MethodPut, "/static/*file", echo("")) }).Should(Panic())
+		})
+		It("should_panic_if_catch_all_is_not_last", func() {
+			Ω(func() { r.HandleFunc(http.MethodGet, "/files/*path/foo", echo("")) }).Should(Panic())
+		})
+		It("should_panic_on_invalid_path", func() {
+			Ω(func() { r.HandleFunc(http.MethodGet, "users", echo("")) }).Should(Panic())
+			Ω(func() { r.HandleFunc(http.MethodGet, "/users/:", echo("")) }).Should(Panic())
+		})
+		It("should_not_panic_on_different_method", func() {
+			Ω(func() { r.HandleFunc(http.MethodPut, "/users/:id", echo("")) }).ShouldNot(Panic())
+		})
+	})
+})
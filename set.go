@@ -0,0 +1,449 @@
+package gorax
+
+import (
+	"sort"
+	"strings"
+)
+
+// Set is a radix tree of keys without values. Its nodes only store whether they are a member, instead of the Nil value
+// a Tree needs for a key without value.
+type Set struct {
+	root setNode
+	size int
+}
+
+// setNode is a node of a Set, with the same layout of edge labels and children as a node of a Tree.
+type setNode struct {
+	key      string
+	children []*setNode
+	member   bool
+}
+
+func (n *setNode) isCompressed() bool {
+	return len(n.key) != len(n.children)
+}
+
+// child returns the child of a branching node for a byte or nil if there is none.
+func (n *setNode) child(c byte) *setNode {
+	i := strings.IndexByte(n.key, c)
+	if i < 0 {
+		return nil
+	}
+
+	return n.children[i]
+}
+
+func (n *setNode) removeChild(child *setNode) {
+	if n.isCompressed() {
+		n.key = ""
+		n.children = nil
+		return
+	}
+
+	for i := range n.children {
+		if n.children[i] == child {
+			n.key = n.key[:i] + n.key[i+1:]
+			n.children = append(n.children[:i], n.children[i+1:]...)
+			break
+		}
+	}
+}
+
+// compress merges the chain of non-members with a single child below a non-member with a single child into its edge
+// label.
+func (n *setNode) compress() {
+	if n.member || len(n.children) != 1 {
+		return
+	}
+
+	for child := n.children[0]; !child.member && len(child.children) == 1; child = n.children[0] {
+		n.key += child.key
+		n.children = child.children
+	}
+}
+
+// NewSet returns a Set containing the keys.
+func NewSet(keys ...string) *Set {
+	s := &Set{}
+	for _, key := range keys {
+		s.Add(key)
+	}
+
+	return s
+}
+
+// Len returns the number of keys in the Set.
+func (s *Set) Len() int {
+	return s.size
+}
+
+// Add adds a key to the Set. Returns 'true' if the key was added.
+func (s *Set) Add(key string) bool {
+	current := &s.root
+	for key != "" {
+		if len(current.key) == 0 {
+			// the remaining key becomes the label of a new child
+			current.key = key
+			current.children = []*setNode{{}}
+			current = current.children[0]
+			break
+		}
+
+		if current.isCompressed() {
+			common := 0
+			for common < len(key) && common < len(current.key) && key[common] == current.key[common] {
+				common += 1
+			}
+			if common == len(current.key) {
+				key = key[common:]
+				current = current.children[0]
+				continue
+			}
+
+			// split the compressed node into a branching node with a single child at the first mismatch
+			rest := current.children[0]
+			if common+1 < len(current.key) {
+				rest = &setNode{key: current.key[common+1:], children: current.children}
+			}
+			branching := &setNode{key: current.key[common : common+1], children: []*setNode{rest}}
+			if common == 0 {
+				current.key, current.children = branching.key, branching.children
+			} else {
+				current.key, current.children = current.key[:common], []*setNode{branching}
+				current = branching
+			}
+			key = key[common:]
+			continue
+		}
+
+		if child := current.child(key[0]); child != nil {
+			key = key[1:]
+			current = child
+			continue
+		}
+
+		child := &setNode{}
+		i := sort.Search(len(current.key), func(i int) bool { return current.key[i] > key[0] })
+		current.key = current.key[:i] + key[:1] + current.key[i:]
+		current.children = append(current.children[:i], append([]*setNode{child}, current.children[i:]...)...)
+
+		key = key[1:]
+		current = child
+	}
+
+	if current.member {
+		return false
+	}
+
+	current.member = true
+	s.size += 1
+
+	return true
+}
+
+// Has returns 'true' if the key is in the Set.
+func (s *Set) Has(key string) bool {
+	current := s.find(key, nil)
+
+	return current != nil && current.member
+}
+
+// Remove removes a key from the Set. Returns 'true' if the key was removed.
+func (s *Set) Remove(key string) bool {
+	var path []*setNode
+	current := s.find(key, &path)
+	if current == nil || !current.member {
+		return false
+	}
+
+	current.member = false
+	s.size -= 1
+
+	// remove the nodes which are no longer needed and compress the remaining ones
+	for i := len(path) - 1; i >= 0; i-- {
+		if len(current.children) == 0 && !current.member {
+			path[i].removeChild(current)
+		} else {
+			current.compress()
+		}
+		current = path[i]
+	}
+	current.compress()
+
+	return true
+}
+
+// find returns the node of a key or nil if there is none, the nodes on the path to it are appended to path.
+func (s *Set) find(key string, path *[]*setNode) *setNode {
+	current := &s.root
+	for key != "" {
+		if path != nil {
+			*path = append(*path, current)
+		}
+
+		if current.isCompressed() {
+			if !strings.HasPrefix(key, current.key) {
+				return nil
+			}
+
+			key = key[len(current.key):]
+			current = current.children[0]
+		} else {
+			child := current.child(key[0])
+			if child == nil {
+				return nil
+			}
+
+			key = key[1:]
+			current = child
+		}
+	}
+
+	return current
+}
+
+// LongestPrefix returns the longest key in the Set which is a prefix of the key.
+func (s *Set) LongestPrefix(key string) (string, bool) {
+	current := &s.root
+
+	var idx int
+	ret, ok := "", current.member
+	for idx < len(key) && len(current.key) > 0 {
+		if current.isCompressed() {
+			if !strings.HasPrefix(key[idx:], current.key) {
+				break
+			}
+
+			idx += len(current.key)
+			current = current.children[0]
+		} else {
+			child := current.child(key[idx])
+			if child == nil {
+				break
+			}
+
+			idx += 1
+			current = child
+		}
+
+		if current.member {
+			ret, ok = key[:idx], true
+		}
+	}
+
+	return ret, ok
+}
+
+// Walk walks the keys of the Set in lexicographical order. Returning 'true' terminates the iteration.
+func (s *Set) Walk(fn func(key string) bool) {
+	s.WalkPrefix("", fn)
+}
+
+// WalkPrefix walks the keys of the Set under a prefix in lexicographical order. Returning 'true' terminates the
+// iteration.
+func (s *Set) WalkPrefix(prefix string, fn func(key string) bool) {
+	current := &s.root
+
+	key := []byte(prefix)
+	for idx := 0; idx < len(prefix); {
+		if len(current.key) == 0 {
+			return
+		}
+
+		if current.isCompressed() {
+			// the prefix may end within the compressed node
+			n := min(len(current.key), len(prefix)-idx)
+			if prefix[idx:idx+n] != current.key[:n] {
+				return
+			}
+
+			key = append(key, current.key[n:]...)
+			idx += len(current.key)
+			current = current.children[0]
+		} else {
+			child := current.child(prefix[idx])
+			if child == nil {
+				return
+			}
+
+			idx += 1
+			current = child
+		}
+	}
+
+	walkSet(current, key, fn)
+}
+
+// walkSet walks the subtree of a node in lexicographical order, returns 'true' if the iteration was terminated.
+func walkSet(n *setNode, key []byte, fn func(key string) bool) bool {
+	if n.member && fn(string(key)) {
+		return true
+	}
+
+	if n.isCompressed() {
+		return walkSet(n.children[0], append(key, n.key...), fn)
+	}
+
+	for i := range n.children {
+		if walkSet(n.children[i], append(key, n.key[i]), fn) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Minimum returns the smallest key of the Set.
+func (s *Set) Minimum() (string, bool) {
+	var key []byte
+	current := &s.root
+	for !current.member && len(current.key) > 0 {
+		if current.isCompressed() {
+			key = append(key, current.key...)
+		} else {
+			key = append(key, current.key[0])
+		}
+		current = current.children[0]
+	}
+
+	return string(key), current.member
+}
+
+// Maximum returns the largest key of the Set.
+func (s *Set) Maximum() (string, bool) {
+	var key []byte
+	current := &s.root
+	for len(current.key) > 0 {
+		if current.isCompressed() {
+			key = append(key, current.key...)
+		} else {
+			key = append(key, current.key[len(current.key)-1])
+		}
+		current = current.children[len(current.children)-1]
+	}
+
+	return string(key), current.member
+}
+
+// Union returns a new Set of the keys which are in either of the Sets.
+func (s *Set) Union(other *Set) *Set {
+	return combine(s, other, func(inS, inOther bool) bool {
+		return inS || inOther
+	})
+}
+
+// Intersect returns a new Set of the keys which are in both Sets.
+func (s *Set) Intersect(other *Set) *Set {
+	return combine(s, other, func(inS, inOther bool) bool {
+		return inS && inOther
+	})
+}
+
+// Difference returns a new Set of the keys which are in the Set, but not in the other Set.
+func (s *Set) Difference(other *Set) *Set {
+	return combine(s, other, func(inS, inOther bool) bool {
+		return inS && !inOther
+	})
+}
+
+// IsSubset returns 'true' if all keys of the Set are in the other Set.
+func (s *Set) IsSubset(other *Set) bool {
+	if s.size == 0 {
+		return true
+	}
+
+	return isSubset(&cursor{n: &s.root}, &cursor{n: &other.root})
+}
+
+// cursor is a position in a Set, either at a node or within the edge label of a compressed node.
+type cursor struct {
+	n   *setNode
+	off int
+}
+
+func (c *cursor) member() bool {
+	return c.off == 0 && c.n.member
+}
+
+// labels returns the bytes of the edges leaving the position.
+func (c *cursor) labels() string {
+	if c.n.isCompressed() {
+		return c.n.key[c.off : c.off+1]
+	}
+
+	return c.n.key
+}
+
+// next returns the position after the i-th edge leaving the position.
+func (c *cursor) next(i int) *cursor {
+	if !c.n.isCompressed() {
+		return &cursor{n: c.n.children[i]}
+	}
+	if c.off+1 < len(c.n.key) {
+		return &cursor{n: c.n, off: c.off + 1}
+	}
+
+	return &cursor{n: c.n.children[0]}
+}
+
+// combine walks two Sets in lockstep and returns a Set of the keys for which keep returns 'true'. Subtrees which are
+// only in one of the Sets are skipped if keep is never 'true' for them.
+func combine(a, b *Set, keep func(inA, inB bool) bool) *Set {
+	ret := &Set{}
+
+	var visit func(ca, cb *cursor, key []byte)
+	visit = func(ca, cb *cursor, key []byte) {
+		if (ca == nil && !keep(false, true)) || (cb == nil && !keep(true, false)) {
+			return
+		}
+
+		inA, inB := ca != nil && ca.member(), cb != nil && cb.member()
+		if (inA || inB) && keep(inA, inB) {
+			ret.Add(string(key))
+		}
+
+		var la, lb string
+		if ca != nil {
+			la = ca.labels()
+		}
+		if cb != nil {
+			lb = cb.labels()
+		}
+
+		// merge the sorted edge labels of both positions
+		for i, j := 0, 0; i < len(la) || j < len(lb); {
+			switch {
+			case j == len(lb) || (i < len(la) && la[i] < lb[j]):
+				visit(ca.next(i), nil, append(key, la[i]))
+				i += 1
+			case i == len(la) || lb[j] < la[i]:
+				visit(nil, cb.next(j), append(key, lb[j]))
+				j += 1
+			default:
+				visit(ca.next(i), cb.next(j), append(key, la[i]))
+				i += 1
+				j += 1
+			}
+		}
+	}
+	visit(&cursor{n: &a.root}, &cursor{n: &b.root}, nil)
+
+	return ret
+}
+
+// isSubset returns 'true' if all keys below the position in a Set are below the position in the other Set.
+func isSubset(a, b *cursor) bool {
+	if a.member() && !b.member() {
+		return false
+	}
+
+	la, lb := a.labels(), b.labels()
+	for i := range la {
+		j := strings.IndexByte(lb, la[i])
+		if j < 0 || !isSubset(a.next(i), b.next(j)) {
+			return false
+		}
+	}
+
+	return true
+}
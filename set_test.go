@@ -0,0 +1,157 @@
+package gorax_test
+
+import (
+	"math/rand"
+	"sort"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/snorwin/gorax"
+)
+
+// setKeys returns the keys of a Set in the order they are walked.
+func setKeys(s *gorax.Set) []string {
+	ret := []string{}
+	s.Walk(func(key string) bool {
+		ret = append(ret, key)
+
+		return false
+	})
+
+	return ret
+}
+
+// sortedKeys returns the sorted keys of a map.
+func sortedKeys(m map[string]bool) []string {
+	ret := []string{}
+	for key := range m {
+		ret = append(ret, key)
+	}
+	sort.Strings(ret)
+
+	return ret
+}
+
+// randSet returns a Set of random keys and a map with the same keys.
+func randSet() (*gorax.Set, map[string]bool) {
+	s, m := gorax.NewSet(), map[string]bool{}
+	for i := 0; i < 100; i++ {
+		key := strings.Map(func(r rune) rune {
+			return rune("abc"[rand.Intn(3)])
+		}, randString(rand.Intn(8)))
+		s.Add(key)
+		m[key] = true
+	}
+
+	return s, m
+}
+
+var _ = Describe("Set", func() {
+	var (
+		s *gorax.Set
+	)
+	BeforeEach(func() {
+		s = gorax.NewSet("foo", "foobar", "foojin", "bar")
+	})
+	It("should_not_fail_if_empty", func() {
+		s := gorax.NewSet()
+		Ω(s.Len()).Should(BeZero())
+		Ω(s.Has("")).Should(BeFalse())
+		Ω(s.Remove("foo")).Should(BeFalse())
+		Ω(setKeys(s)).Should(BeEmpty())
+
+		_, ok := s.Minimum()
+		Ω(ok).Should(BeFalse())
+		_, ok = s.Maximum()
+		Ω(ok).Should(BeFalse())
+		_, ok = s.LongestPrefix("foo")
+		Ω(ok).Should(BeFalse())
+	})
+	It("should_add_has_and_remove", func() {
+		Ω(s.Add("foo")).Should(BeFalse())
+		Ω(s.Add("fo")).Should(BeTrue())
+		Ω(s.Len()).Should(Equal(5))
+		Ω(s.Has("fo")).Should(BeTrue())
+		Ω(s.Has("foob")).Should(BeFalse())
+
+		Ω(s.Remove("foob")).Should(BeFalse())
+		Ω(s.Remove("foo")).Should(BeTrue())
+		Ω(s.Has("foo")).Should(BeFalse())
+		Ω(s.Has("foobar")).Should(BeTrue())
+		Ω(s.Len()).Should(Equal(4))
+	})
+	It("should_find_longest_prefix", func() {
+		key, ok := s.LongestPrefix("foobaz")
+		Ω(ok).Should(BeTrue())
+		Ω(key).Should(Equal("foo"))
+
+		key, ok = s.LongestPrefix("foobars")
+		Ω(ok).Should(BeTrue())
+		Ω(key).Should(Equal("foobar"))
+	})
+	It("should_walk_prefix", func() {
+		var actual []string
+		s.WalkPrefix("foob", func(key string) bool {
+			actual = append(actual, key)
+
+			return false
+		})
+		Ω(actual).Should(Equal([]string{"foobar"}))
+	})
+	It("should_find_minimum_and_maximum", func() {
+		key, ok := s.Minimum()
+		Ω(ok).Should(BeTrue())
+		Ω(key).Should(Equal("bar"))
+
+		key, ok = s.Maximum()
+		Ω(ok).Should(BeTrue())
+		Ω(key).Should(Equal("foojin"))
+	})
+	It("should_match_map", func() {
+		s, m := randSet()
+		Ω(setKeys(s)).Should(Equal(sortedKeys(m)))
+		Ω(s.Len()).Should(Equal(len(m)))
+
+		for key := range m {
+			if rand.Intn(2) == 0 {
+				Ω(s.Remove(key)).Should(BeTrue())
+				delete(m, key)
+			}
+		}
+		Ω(setKeys(s)).Should(Equal(sortedKeys(m)))
+		Ω(s.Len()).Should(Equal(len(m)))
+		for key := range m {
+			Ω(s.Has(key)).Should(BeTrue())
+		}
+	})
+	It("should_compute_set_algebra", func() {
+		for i := 0; i < 100; i++ {
+			a, ma := randSet()
+			b, mb := randSet()
+
+			union, intersection, difference := map[string]bool{}, map[string]bool{}, map[string]bool{}
+			for key := range ma {
+				union[key] = true
+				if mb[key] {
+					intersection[key] = true
+				} else {
+					difference[key] = true
+				}
+			}
+			for key := range mb {
+				union[key] = true
+			}
+
+			Ω(setKeys(a.Union(b))).Should(Equal(sortedKeys(union)))
+			Ω(setKeys(a.Intersect(b))).Should(Equal(sortedKeys(intersection)))
+			Ω(setKeys(a.Difference(b))).Should(Equal(sortedKeys(difference)))
+			Ω(a.Union(b).Len()).Should(Equal(len(union)))
+
+			Ω(a.IsSubset(a.Union(b))).Should(BeTrue())
+			Ω(a.Intersect(b).IsSubset(b)).Should(BeTrue())
+			Ω(a.IsSubset(b)).Should(Equal(len(difference) == 0))
+		}
+	})
+})
@@ -0,0 +1,93 @@
+package gorax
+
+import "unsafe"
+
+// Stats describes the shape and memory consumption of a Tree.
+type Stats struct {
+	// Nodes is the number of nodes.
+	Nodes int
+	// CompressedNodes is the number of nodes with a compressed edge to a single child.
+	CompressedNodes int
+	// BranchingNodes is the number of nodes with an edge of a single byte to each child.
+	BranchingNodes int
+	// LeafNodes is the number of nodes without children.
+	LeafNodes int
+	// Keys is the number of keys.
+	Keys int
+	// MaxDepth is the maximum number of edges from the root to a node.
+	MaxDepth int
+	// AverageDepth is the average number of edges from the root to a key.
+	AverageDepth float64
+	// EdgeBytes is the total number of bytes of all edge labels.
+	EdgeBytes int
+	// FanOut maps the number of children to the number of nodes having that many children.
+	FanOut map[int]int
+	// HeapBytes is an estimate of the bytes allocated by the nodes, including edge labels, the capacity of the
+	// children slices, the lookups of wide branching nodes and the caches of the subtrees, but excluding the values.
+	HeapBytes int
+}
+
+// Stats walks the Tree and returns statistics about its nodes.
+func (t *Tree) Stats() Stats {
+	t.expire()
+
+	ret := Stats{
+		FanOut: map[int]int{},
+	}
+
+	type item struct {
+		node  *node
+		depth int
+	}
+
+	var depths int
+	items := []item{{node: &t.root}}
+	for len(items) > 0 {
+		// pop node
+		current := items[len(items)-1]
+		items = items[:len(items)-1]
+
+		n := current.node
+
+		ret.Nodes += 1
+		switch {
+		case n.isLeaf():
+			ret.LeafNodes += 1
+		case n.isCompressed():
+			ret.CompressedNodes += 1
+		default:
+			ret.BranchingNodes += 1
+		}
+		if n.isKey() {
+			ret.Keys += 1
+			depths += current.depth
+		}
+		if current.depth > ret.MaxDepth {
+			ret.MaxDepth = current.depth
+		}
+
+		ret.EdgeBytes += len(n.key)
+		ret.FanOut[len(n.children)] += 1
+		ret.HeapBytes += int(unsafe.Sizeof(*n)) + len(n.key) + cap(n.children)*int(unsafe.Sizeof(n))
+		if n.index != nil {
+			ret.HeapBytes += int(unsafe.Sizeof(*n.index))
+		}
+		if n.direct != nil {
+			ret.HeapBytes += int(unsafe.Sizeof(*n.direct))
+		}
+		if n.cache != nil {
+			ret.HeapBytes += int(unsafe.Sizeof(*n.cache))
+		}
+
+		// push child nodes
+		for _, child := range n.children {
+			items = append(items, item{node: child, depth: current.depth + 1})
+		}
+	}
+
+	if ret.Keys > 0 {
+		ret.AverageDepth = float64(depths) / float64(ret.Keys)
+	}
+
+	return ret
+}
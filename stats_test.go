@@ -0,0 +1,56 @@
+package gorax_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/snorwin/gorax"
+)
+
+var _ = Describe("Tree", func() {
+	Context("Stats", func() {
+		It("should_not_fail_if_empty", func() {
+			stats := gorax.New().Stats()
+			Ω(stats.Nodes).Should(Equal(1))
+			Ω(stats.LeafNodes).Should(Equal(1))
+			Ω(stats.Keys).Should(Equal(0))
+			Ω(stats.AverageDepth).Should(BeZero())
+		})
+		It("should_describe_tree", func() {
+			t := gorax.FromMap(map[string]interface{}{
+				"foo":    1,
+				"foobar": 2,
+				"foojin": 3,
+				"bar":    4,
+			})
+
+			// "" -[b,f]-> ("b" -"ar"-> "bar", "f" -"oo"-> "foo" -[b,j]-> ("foob" -"ar"-> "foobar", "fooj" -"in"-> "foojin"))
+			stats := t.Stats()
+			Ω(stats.Nodes).Should(Equal(9))
+			Ω(stats.BranchingNodes).Should(Equal(2))
+			Ω(stats.CompressedNodes).Should(Equal(4))
+			Ω(stats.LeafNodes).Should(Equal(3))
+			Ω(stats.Keys).Should(Equal(t.Len()))
+			Ω(stats.MaxDepth).Should(Equal(4))
+			Ω(stats.AverageDepth).Should(Equal(3.0))
+			Ω(stats.EdgeBytes).Should(Equal(12))
+			Ω(stats.FanOut).Should(Equal(map[int]int{0: 3, 1: 4, 2: 2}))
+			Ω(stats.HeapBytes).Should(BeNumerically(">", stats.EdgeBytes))
+		})
+		It("should_allocate_caches_on_first_use", func() {
+			m := map[string]interface{}{
+				"foo":    1,
+				"foobar": 2,
+				"foojin": 3,
+				"bar":    4,
+			}
+			plain := gorax.FromMap(m).Stats().HeapBytes
+
+			t := gorax.FromMap(m, gorax.WithHash(nil))
+			Ω(t.Stats().HeapBytes).Should(Equal(plain))
+
+			t.RootHash()
+			Ω(t.Stats().HeapBytes).Should(BeNumerically(">", plain))
+		})
+	})
+})
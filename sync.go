@@ -0,0 +1,275 @@
+package gorax
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"io"
+	"strings"
+)
+
+// ErrNoHash is returned by the synchronization of a Tree which is not configured WithHash.
+var ErrNoHash = errors.New("gorax: tree is not configured with hash")
+
+// SyncServer serves a Tree to SyncClients, which reconcile their replica with it. Accesses to the Tree from other
+// goroutines must be synchronized with Serve.
+type SyncServer struct {
+	tree *Tree
+}
+
+// NewSyncServer returns a SyncServer for a Tree configured WithHash.
+func NewSyncServer(t *Tree) *SyncServer {
+	return &SyncServer{
+		tree: t,
+	}
+}
+
+// SyncClient reconciles a replica with the Tree of a SyncServer. The subtree hashes of both Trees are compared top-down
+// and only the keys of mismatched subtrees are transferred. Accesses to the replica from other goroutines must be
+// synchronized with Sync.
+type SyncClient struct {
+	tree *Tree
+
+	// Decode decodes the values transferred in the encoding of the Encoder of the SyncServer, defaults to DecodeValue.
+	// The Encoder of the replica must encode the decoded value to the same bytes.
+	Decode func(value []byte) (interface{}, error)
+}
+
+// NewSyncClient returns a SyncClient for a replica configured WithHash.
+func NewSyncClient(t *Tree) *SyncClient {
+	return &SyncClient{
+		tree: t,
+	}
+}
+
+// SyncResult reports what a synchronization changed.
+type SyncResult struct {
+	// Requests is the number of subtrees and values requested from the SyncServer.
+	Requests int
+
+	// Inserted is the number of keys inserted or updated in the replica.
+	Inserted int
+
+	// Deleted is the number of keys deleted from the replica.
+	Deleted int
+}
+
+const (
+	syncOpNode = iota
+	syncOpValue
+	syncOpDone
+)
+
+// syncRequest requests the subtree of a prefix, or the value of a key. For a subtree the key and hash of the
+// corresponding subtree in the replica are sent along, to respond with 'Equal' if they match.
+type syncRequest struct {
+	Op     int
+	Prefix string
+	Key    string
+	Hash   []byte
+}
+
+// syncResponse is the subtree of a prefix or the value of a key.
+type syncResponse struct {
+	Found bool
+	Equal bool
+
+	Key       string
+	ValueHash []byte
+	Labels    []string
+	Hashes    [][]byte
+
+	Value []byte
+}
+
+// Serve serves the requests of a SyncClient until the synchronization is done.
+func (s *SyncServer) Serve(rw io.ReadWriter) error {
+	if s.tree.encode == nil {
+		return ErrNoHash
+	}
+
+	enc, dec := gob.NewEncoder(rw), gob.NewDecoder(rw)
+	for {
+		var req syncRequest
+		if err := dec.Decode(&req); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+
+		s.tree.expire()
+
+		var resp syncResponse
+		switch req.Op {
+		case syncOpNode:
+			resp = s.node(req)
+		case syncOpValue:
+			if current := s.tree.lookup(req.Prefix); current != nil {
+				resp.Found = true
+				resp.Value = s.tree.encode(current.getValue())
+			}
+		case syncOpDone:
+			return nil
+		}
+
+		if err := enc.Encode(resp); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *SyncServer) node(req syncRequest) syncResponse {
+	current, key := s.tree.subtree(req.Prefix)
+	if current == nil {
+		return syncResponse{}
+	}
+
+	resp := syncResponse{
+		Found: true,
+		Equal: key == req.Key && bytes.Equal(s.tree.hash(current), req.Hash),
+	}
+	if resp.Equal {
+		return resp
+	}
+
+	resp.Key = key
+	if current.isKey() {
+		resp.ValueHash = hashValue(s.tree.encode(current.getValue()))
+	}
+	resp.Labels = labels(current)
+	resp.Hashes = make([][]byte, len(current.children))
+	for i, child := range current.children {
+		resp.Hashes[i] = s.tree.hash(child)
+	}
+
+	return resp
+}
+
+// Sync reconciles the replica with the Tree of the SyncServer, afterwards both contain the same keys and values.
+func (c *SyncClient) Sync(rw io.ReadWriter) (SyncResult, error) {
+	var result SyncResult
+	if c.tree.encode == nil {
+		return result, ErrNoHash
+	}
+
+	c.tree.expire()
+
+	enc, dec := gob.NewEncoder(rw), gob.NewDecoder(rw)
+	request := func(req syncRequest) (syncResponse, error) {
+		var resp syncResponse
+		if err := enc.Encode(req); err != nil {
+			return resp, err
+		}
+		result.Requests += 1
+
+		return resp, dec.Decode(&resp)
+	}
+
+	prefixes := []string{""}
+	for len(prefixes) > 0 {
+		prefix := prefixes[len(prefixes)-1]
+		prefixes = prefixes[:len(prefixes)-1]
+
+		req := syncRequest{Op: syncOpNode, Prefix: prefix}
+		if current, key := c.tree.subtree(prefix); current != nil {
+			req.Key, req.Hash = key, c.tree.hash(current)
+		}
+
+		resp, err := request(req)
+		if err != nil {
+			return result, err
+		}
+		if resp.Equal {
+			continue
+		}
+		if !resp.Found {
+			result.Deleted += c.tree.deletePrefix(prefix)
+			continue
+		}
+
+		// delete the keys which are neither the key of the subtree nor below one of its children
+		for _, key := range c.tree.stale(prefix, resp.Key, resp.ValueHash != nil, resp.Labels) {
+			c.tree.remove(key)
+			result.Deleted += 1
+		}
+
+		// transfer the value of the key of the subtree if it differs
+		if resp.ValueHash != nil {
+			current := c.tree.lookup(resp.Key)
+			if current == nil || !bytes.Equal(hashValue(c.tree.encode(current.getValue())), resp.ValueHash) {
+				value, err := request(syncRequest{Op: syncOpValue, Prefix: resp.Key})
+				if err != nil {
+					return result, err
+				}
+				if value.Found {
+					v, err := c.decode(value.Value)
+					if err != nil {
+						return result, err
+					}
+					c.tree.Insert(resp.Key, v)
+					result.Inserted += 1
+				}
+			}
+		}
+
+		// continue with the children whose subtree differs
+		for i := len(resp.Labels) - 1; i >= 0; i-- {
+			key := resp.Key + resp.Labels[i]
+			if current, k := c.tree.subtree(key); current == nil || k != key || !bytes.Equal(c.tree.hash(current), resp.Hashes[i]) {
+				prefixes = append(prefixes, key)
+			}
+		}
+	}
+
+	return result, enc.Encode(syncRequest{Op: syncOpDone})
+}
+
+func (c *SyncClient) decode(value []byte) (interface{}, error) {
+	if c.Decode != nil {
+		return c.Decode(value)
+	}
+
+	return DecodeValue(value)
+}
+
+// subtree returns the topmost node with all keys under a prefix and its key, or nil if there are no keys under the
+// prefix.
+func (t *Tree) subtree(prefix string) (*node, string) {
+	return t.findSubtree(prefix, nil)
+}
+
+// stale returns the keys under a prefix which are neither the key itself, if 'isKey', nor below the key followed by
+// one of the labels.
+func (t *Tree) stale(prefix, key string, isKey bool, labels []string) []string {
+	current, k := t.subtree(prefix)
+	if current == nil {
+		return nil
+	}
+
+	var ret []string
+
+	var visit func(n *node, k string)
+	visit = func(n *node, k string) {
+		for _, label := range labels {
+			if strings.HasPrefix(k, key+label) {
+				return
+			}
+		}
+
+		if n.isKey() && (!isKey || k != key) {
+			ret = append(ret, k)
+		}
+
+		if n.isCompressed() {
+			visit(n.children[0], k+n.key)
+		} else {
+			for i, child := range n.children {
+				visit(child, k+n.key[i:i+1])
+			}
+		}
+	}
+	visit(current, k)
+
+	return ret
+}
@@ -0,0 +1,91 @@
+package gorax_test
+
+import (
+	"fmt"
+	"math/rand"
+	"net"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/snorwin/gorax"
+)
+
+// syncTrees synchronizes the replica with the Tree over a net.Pipe.
+func syncTrees(replica, t *gorax.Tree) gorax.SyncResult {
+	server, client := net.Pipe()
+	defer client.Close()
+
+	done := make(chan error, 1)
+	go func() {
+		defer server.Close()
+		done <- gorax.NewSyncServer(t).Serve(server)
+	}()
+
+	result, err := gorax.NewSyncClient(replica).Sync(client)
+	Ω(err).ShouldNot(HaveOccurred())
+	Ω(<-done).ShouldNot(HaveOccurred())
+
+	return result
+}
+
+var _ = Describe("Sync", func() {
+	var (
+		t       *gorax.Tree
+		replica *gorax.Tree
+	)
+	BeforeEach(func() {
+		t = gorax.New(gorax.WithHash(nil))
+		replica = gorax.New(gorax.WithHash(nil))
+		for i := 0; i < 1000; i++ {
+			key, value := fmt.Sprintf("key/%d", i), fmt.Sprintf("value%d", i)
+			t.Insert(key, value)
+			replica.Insert(key, value)
+		}
+	})
+	It("should_fail_without_hash", func() {
+		_, err := gorax.NewSyncClient(gorax.New()).Sync(nil)
+		Ω(err).Should(MatchError(gorax.ErrNoHash))
+		Ω(gorax.NewSyncServer(gorax.New()).Serve(nil)).Should(MatchError(gorax.ErrNoHash))
+	})
+	It("should_not_transfer_equal_trees", func() {
+		result := syncTrees(replica, t)
+		Ω(result).Should(Equal(gorax.SyncResult{Requests: 1}))
+	})
+	It("should_transfer_only_mismatched_keys", func() {
+		t.Insert("key/500", "changed")
+		t.Insert("key/1000", "value1000")
+		t.Delete("key/42")
+
+		result := syncTrees(replica, t)
+		Ω(result.Inserted).Should(Equal(2))
+		Ω(result.Deleted).Should(Equal(1))
+		Ω(result.Requests).Should(BeNumerically("<", 30))
+
+		Ω(replica.ToMap()).Should(Equal(t.ToMap()))
+		Ω(replica.RootHash()).Should(Equal(t.RootHash()))
+	})
+	It("should_sync_empty_trees", func() {
+		result := syncTrees(replica, gorax.New(gorax.WithHash(nil)))
+		Ω(result.Deleted).Should(Equal(1000))
+		Ω(replica.Len()).Should(BeZero())
+
+		result = syncTrees(replica, t)
+		Ω(result.Inserted).Should(Equal(1000))
+		Ω(replica.ToMap()).Should(Equal(t.ToMap()))
+	})
+	It("should_sync_random_trees", func() {
+		for i := 0; i < 20; i++ {
+			t := gorax.New(gorax.WithHash(nil))
+			replica := gorax.New(gorax.WithHash(nil))
+			for j := 0; j < 200; j++ {
+				t.Insert(randString(rand.Intn(6)), fmt.Sprint(rand.Intn(3)))
+				replica.Insert(randString(rand.Intn(6)), fmt.Sprint(rand.Intn(3)))
+			}
+
+			syncTrees(replica, t)
+			Ω(replica.ToMap()).Should(Equal(t.ToMap()))
+			Ω(replica.RootHash()).Should(Equal(t.RootHash()))
+		}
+	})
+})
@@ -1,24 +1,61 @@
 package gorax
 
 import (
+	"bytes"
 	"sort"
 	"strings"
+	"time"
 )
 
-// Tree implements a radix tree.
+// Tree implements a radix tree. Reads like Get, LongestPrefix, Walk and Len are safe for concurrent readers as long as
+// the Tree is not mutated, except for the following reads which update the Tree and require exclusive access:
+//   - every read of a Tree with keys inserted by InsertWithTTL, as expired keys are deleted,
+//   - Get and LongestPrefix of a Tree with a capacity, as the use of the keys is tracked for the eviction,
+//   - Aggregate, RootHash, Prove, RandomKey and the SyncServer, as they fill the caches of the subtrees.
 type Tree struct {
 	root node
 	size int
+
+	separator byte
+	monoid    Monoid
+
+	clock     func() time.Time
+	evict     EvictFn
+	deadlines deadlines
+
+	bounds []*bound
+	policy EvictionPolicy
+
+	normalizer func(string) string
+	encode     Encoder
+
+	// checkValues is set if the values are encoded by EncodeValue, whose supported types are checked by Insert
+	checkValues bool
+
+	arena arena
+
+	tx *Tx
+
+	// counting is set once the key counts of the subtrees are cached by RandomKey, to be invalidated by every insert
+	counting bool
 }
 
-// New returns an empty Tree.
-func New() *Tree {
-	return &Tree{}
+// New returns an empty Tree configured with the given options.
+func New(opts ...Option) *Tree {
+	t := &Tree{}
+	for _, opt := range opts {
+		opt(t)
+	}
+	for _, b := range t.bounds {
+		b.prefix = t.normalizeKey(b.prefix)
+	}
+
+	return t
 }
 
 // FromMap returns a new Tree containing the keys from an existing map.
-func FromMap(values map[string]interface{}) *Tree {
-	t := New()
+func FromMap(values map[string]interface{}, opts ...Option) *Tree {
+	t := New(opts...)
 	for k, v := range values {
 		t.Insert(k, v)
 	}
@@ -40,38 +77,89 @@ func (t *Tree) ToMap() map[string]interface{} {
 
 // Len returns the number of elements in the Tree.
 func (t *Tree) Len() int {
+	t.expire()
+
 	return t.size
 }
 
+// Reset removes all entries from the Tree without calling the EvictFn. A Tree configured with WithArena keeps its
+// allocated nodes to be reused by subsequent inserts, but allocates new chunks for the edge labels. The removed
+// entries are journaled by an open transaction.
+func (t *Tree) Reset() {
+	if t.tx != nil {
+		walk(&t.root, nil, func(key []byte, n *node) bool {
+			if n.isKey() {
+				t.record(unsafeString(key), n)
+			}
+			return false
+		})
+	}
+
+	t.root = node{}
+	t.size = 0
+	t.deadlines = t.deadlines[:0]
+	for _, b := range t.bounds {
+		b.usage = nil
+	}
+	t.arena.reset()
+}
+
 // Insert adds a new entry or updates an existing entry. Returns 'true' if entry was added.
 func (t *Tree) Insert(key string, value interface{}) bool {
+	if t.checkValues {
+		checkValue(value)
+	}
 	if value == nil {
 		value = Nil{}
 	}
+	key, value = t.normalize(key, value)
+
+	t.expire()
+
+	if t.tx != nil {
+		t.record(key, t.lookup(key))
+	}
 
 	ok := t.insert(key, value, true)
 	if ok {
 		t.size += 1
 	}
+	t.update()
+
+	if len(t.bounds) > 0 {
+		t.touch(key)
+		t.shrink(key)
+	}
 	return ok
 }
 
 // Get is used to lookup a specific key and returns the value and if it was found.
 func (t *Tree) Get(key string) (interface{}, bool) {
-	current, idx, split := t.find(key, nil)
-	if idx != len(key) || (current.isCompressed() && split != 0) || !current.isKey() {
+	t.expire()
+
+	key = t.normalizeKey(key)
+
+	current := t.lookup(key)
+	if current == nil {
 		return nil, false
 	}
 
+	t.touch(key)
+
 	return current.getValue(), true
 }
 
 // LongestPrefix is like Get, but instead of an exact match, it will return the longest prefix match.
+// If the Tree has a separator, only prefixes ending at a segment boundary are matched.
 func (t *Tree) LongestPrefix(prefix string) (string, interface{}, bool) {
+	t.expire()
+
+	prefix = t.normalizeKey(prefix)
+
 	var current *node
 	var currentKey string
 	t.find(prefix, func(key string, node *node) bool {
-		if node.isKey() {
+		if node.isKey() && t.isBoundary(prefix, len(key)) {
 			current = node
 			currentKey = key
 		}
@@ -83,95 +171,76 @@ func (t *Tree) LongestPrefix(prefix string) (string, interface{}, bool) {
 		return "", nil, false
 	}
 
+	t.touch(currentKey)
+
+	if original, ok := current.original(); ok {
+		currentKey = original
+	}
+
 	return currentKey, current.getValue(), true
 }
 
 // Delete deletes a key and returns the previous value and if it was deleted.
 func (t *Tree) Delete(key string) (interface{}, bool) {
-	var nodes []*node
-	current, idx, split := t.find(key, func(_ string, n *node) bool {
-		nodes = append(nodes, n)
-		return false
-	})
-	if idx != len(key) || (current.isCompressed() && split != 0) || !current.isKey() {
-		return nil, false
-	}
-
-	value := current.getValue()
-	current.value = nil
+	t.expire()
 
-	t.size -= 1
+	_, value, ok := t.remove(t.normalizeKey(key))
 
-	t.delete(nodes[:len(nodes)-1], current)
-
-	return value, true
+	return value, ok
 }
 
 // DeletePrefix deletes the subtree under a prefix Returns how many nodes were deleted.
-// Use this to delete large subtrees efficiently.
+// Use this to delete large subtrees efficiently. If the Tree has a separator, only the prefix itself and the keys of
+// the segments below it are deleted.
 func (t *Tree) DeletePrefix(prefix string) int {
-	var counter int
+	t.expire()
 
-	var nodes []*node
-	current, idx, split := t.find(prefix, func(_ string, n *node) bool {
-		nodes = append(nodes, n)
-		return false
-	})
+	prefix = t.normalizeKey(prefix)
 
-	if len(prefix) == idx+split {
-		walk(current, func(key string, node *node) bool {
-			if node.isKey() {
-				counter += 1
-			}
-			return false
-		})
-
-		t.size -= counter
-
-		current.key = ""
-		current.children = nil
+	if t.isSegmentPrefix(prefix) {
+		return t.deletePrefix(prefix)
+	}
 
-		t.delete(nodes, current)
+	var counter int
+	if _, _, ok := t.remove(prefix); ok {
+		counter += 1
 	}
 
-	return counter
+	return counter + t.deletePrefix(t.segmentPrefix(prefix))
 }
 
 // WalkFn is used when walking the Tree. Takes a key and value, returning 'true' if iteration should be terminated.
 type WalkFn func(key string, value interface{}) bool
 
-// Walk walks the Tree
+// Walk walks the Tree in lexicographical order.
 func (t *Tree) Walk(fn WalkFn) {
-	walk(&t.root, func(key string, node *node) bool {
-		// call WalkFn
-		if node.isKey() {
-			return fn(key, node.getValue())
-		}
-
-		return false
+	t.WalkBytes(func(key []byte, value interface{}) bool {
+		return fn(string(key), value)
 	})
 }
 
-// WalkPrefix walks the Tree under a prefix.
+// WalkPrefix walks the Tree under a prefix. If the Tree has a separator, only the prefix itself and the keys of the
+// segments below it are walked.
 func (t *Tree) WalkPrefix(prefix string, fn WalkFn) {
-	current, idx, split := t.find(prefix, nil)
-	if len(prefix) == idx+split {
-		walk(current, func(key string, node *node) bool {
-			// call WalkFn
-			if node.isKey() {
-				return fn(prefix+key, node.getValue())
-			}
+	t.expire()
 
-			return false
-		})
-	}
+	t.walkKeys(t.normalizeKey(prefix), originalKeys(func(key []byte, value interface{}) bool {
+		return fn(string(key), value)
+	}))
 }
 
 // WalkPath is used to walk the Tree, but only visiting nodes from the root down to a given leaf.
+// If the Tree has a separator, only keys ending at a segment boundary of the path are visited.
 func (t *Tree) WalkPath(path string, fn WalkFn) {
+	t.expire()
+
+	path = t.normalizeKey(path)
 	t.find(path, func(key string, node *node) bool {
 		// call WalkFn
-		if node.isKey() {
+		if node.isKey() && t.isBoundary(path, len(key)) {
+			if original, ok := node.original(); ok {
+				key = original
+			}
 			return fn(key, node.getValue())
 		}
 
@@ -179,8 +248,46 @@ func (t *Tree) WalkPath(path string, fn WalkFn) {
 	})
 }
 
+// Children returns the sorted names of the immediate child segments under a path, like a directory listing. A child
+// segment is listed if it is a key itself or if there are keys below it. Returns nil if the Tree has no separator.
+func (t *Tree) Children(path string) []string {
+	if t.separator == 0 {
+		return nil
+	}
+
+	t.expire()
+
+	path = t.normalizeKey(path)
+	if !t.isSegmentPrefix(path) {
+		path = t.segmentPrefix(path)
+	}
+
+	segments := map[string]struct{}{}
+	t.walkPrefix(path, func(key []byte, _ *node) bool {
+		segment := key[len(path):]
+		if i := bytes.IndexByte(segment, t.separator); i >= 0 {
+			segment = segment[:i]
+		}
+		if len(segment) > 0 {
+			segments[string(segment)] = struct{}{}
+		}
+
+		return false
+	})
+
+	ret := make([]string, 0, len(segments))
+	for segment := range segments {
+		ret = append(ret, segment)
+	}
+	sort.Strings(ret)
+
+	return ret
+}
+
 // Minimum returns the minimum value in the Tree.
 func (t *Tree) Minimum() (string, interface{}, bool) {
+	t.expire()
+
 	current := &t.root
 
 	var ret []byte
@@ -197,11 +304,17 @@ func (t *Tree) Minimum() (string, interface{}, bool) {
 		current = current.children[0]
 	}
 
+	if original, ok := current.original(); ok {
+		return original, current.getValue(), true
+	}
+
 	return string(ret), current.getValue(), current.isKey()
 }
 
 // Maximum returns the maximum value in the Tree.
 func (t *Tree) Maximum() (string, interface{}, bool) {
+	t.expire()
+
 	current := &t.root
 
 	var ret []byte
@@ -215,12 +328,185 @@ func (t *Tree) Maximum() (string, interface{}, bool) {
 		}
 	}
 
+	if original, ok := current.original(); ok {
+		return original, current.getValue(), true
+	}
+
 	return string(ret), current.getValue(), current.isKey()
 }
 
-func (t *Tree) insert(key string, value interface{}, overwrite bool) bool {
-	// find the radix tree as far as possible
+// walkKeys walks the keys under a normalized prefix. If the Tree has a separator, only the prefix itself and the keys
+// of the segments below it are walked.
+func (t *Tree) walkKeys(prefix string, fn func([]byte, *node) bool) {
+	if t.isSegmentPrefix(prefix) {
+		t.walkPrefix(prefix, fn)
+		return
+	}
+
+	if current := t.lookup(prefix); current != nil && fn([]byte(prefix), current) {
+		return
+	}
+
+	t.walkPrefix(t.segmentPrefix(prefix), fn)
+}
+
+// walkPrefix walks all keys under a prefix.
+func (t *Tree) walkPrefix(prefix string, fn func([]byte, *node) bool) {
+	current, key := t.findSubtree(prefix, nil)
+	if current == nil {
+		return
+	}
+
+	buf := make([]byte, 0, 64)
+	buf = append(buf, key...)
+
+	walk(current, buf, func(key []byte, node *node) bool {
+		if node.isKey() {
+			return fn(key, node)
+		}
+
+		return false
+	})
+}
+
+// remove removes a normalized key, returns its original key and value and if it was removed.
+func (t *Tree) remove(key string) (string, interface{}, bool) {
+	var nodes []*node
+	current, idx, split := t.find(key, func(_ string, n *node) bool {
+		nodes = append(nodes, n)
+		return invalidate("", n)
+	})
+	if idx != len(key) || (current.isCompressed() && split != 0) || !current.isKey() {
+		t.update()
+		return "", nil, false
+	}
+
+	original := key
+	if k, ok := current.original(); ok {
+		original = k
+	}
+	t.record(key, current)
+
+	value := current.getValue()
+	current.value = nil
+
+	t.size -= 1
+
+	t.delete(nodes[:len(nodes)-1], current)
+	t.update()
+	t.untrack(key)
+
+	return original, value, true
+}
+
+func (t *Tree) deletePrefix(prefix string) int {
+	var counter int
+
+	var nodes []*node
+	start, key := t.findSubtree(prefix, func(_ string, n *node) bool {
+		nodes = append(nodes, n)
+		return invalidate("", n)
+	})
+	defer t.update()
+	if start == nil {
+		return 0
+	}
+
+	// the last node on the path is the start itself, or the compressed node in which the prefix ends
+	current := nodes[len(nodes)-1]
+
+	walk(start, []byte(key), func(key []byte, node *node) bool {
+		if node.isKey() {
+			counter += 1
+			if t.tx != nil {
+				t.record(unsafeString(key), node)
+			}
+			if len(t.bounds) > 0 {
+				t.untrack(unsafeString(key))
+			}
+		}
+		return false
+	})
+
+	t.size -= counter
+
+	if start == current {
+		current.value = nil
+	}
+	for _, child := range current.children {
+		t.arena.releaseAll(child)
+	}
+	current.removeChildren()
+
+	if !current.isKey() {
+		t.delete(nodes[:len(nodes)-1], current)
+	}
+
+	return counter
+}
+
+// isSegmentPrefix returns 'true' if all keys starting with the prefix are at a segment boundary after the prefix.
+func (t *Tree) isSegmentPrefix(prefix string) bool {
+	return t.separator == 0 || prefix == "" || prefix[len(prefix)-1] == t.separator
+}
+
+// segmentPrefix returns the prefix of all keys of the segments below a key.
+func (t *Tree) segmentPrefix(key string) string {
+	return key + string([]byte{t.separator})
+}
+
+// isBoundary returns 'true' if the position i of the key is at a segment boundary. Without separator every position
+// is a boundary.
+func (t *Tree) isBoundary(key string, i int) bool {
+	return t.separator == 0 || i == 0 || i == len(key) || key[i] == t.separator || key[i-1] == t.separator
+}
+
+// findSubtree returns the topmost node with all keys under a prefix and its key, or nil if there are no keys under the
+// prefix. The nodes on the path are passed to fn like by find.
+func (t *Tree) findSubtree(prefix string, fn func(string, *node) bool) (*node, string) {
+	current, idx, split := t.find(prefix, fn)
+	if idx != len(prefix) {
+		return nil, ""
+	}
+
+	key := prefix
+	if split != 0 {
+		// the prefix ends within a compressed node, all keys are below its child
+		key = prefix[:idx-split] + current.key
+		current = current.children[0]
+	}
+
+	if !current.isKey() && len(current.children) == 0 {
+		return nil, ""
+	}
+
+	return current, key
+}
+
+// lookup returns the node of a key or nil if the key is not in the Tree.
+func (t *Tree) lookup(key string) *node {
 	current, idx, split := t.find(key, nil)
+	if idx != len(key) || (current.isCompressed() && split != 0) || !current.isKey() {
+		return nil
+	}
+
+	return current
+}
+
+// update updates the invalidated aggregates after a mutation.
+func (t *Tree) update() {
+	if t.monoid != nil {
+		t.aggregate(&t.root)
+	}
+}
+
+func (t *Tree) insert(key string, value interface{}, overwrite bool) bool {
+	// find the radix tree as far as possible, invalidating the aggregates, hashes and key counts on the path
+	var fn func(string, *node) bool
+	if t.monoid != nil || t.encode != nil || t.counting {
+		fn = invalidate
+	}
+	current, idx, split := t.find(key, fn)
 
 	// insert value if key is already part of the tree and not in the middle of a compressed node
 	if idx == len(key) && (!current.isCompressed() || split == 0) {
@@ -240,32 +526,29 @@ func (t *Tree) insert(key string, value interface{}, overwrite bool) bool {
 	// split compressed node
 	if current.isCompressed() {
 		if idx != len(key) {
-			newChild := &node{}
+			newChild := t.arena.newNode()
 
 			if split == 0 {
-				current.children = []*node{
-					{
-						key:      current.key[1:],
-						children: current.children,
-					},
-				}
+				oldChild := t.arena.newNode()
+				oldChild.key = current.key[1:]
+				oldChild.children = current.children
 
-				current.key = string(current.key[0])
-				current.addChild(string(key[idx]), newChild)
+				current.key = current.key[:1]
+				current.children = []*node{oldChild}
+				current.addChild(&t.arena, key[idx:idx+1], newChild)
 			} else {
 				var oldChild *node
 				if len(current.key) == split+1 {
 					oldChild = current.children[0]
 				} else {
-					oldChild = &node{
-						key:      current.key[split+1:],
-						children: current.children,
-					}
+					oldChild = t.arena.newNode()
+					oldChild.key = current.key[split+1:]
+					oldChild.children = current.children
 				}
 
-				splitNode := &node{}
-				splitNode.addChild(string(current.key[split]), oldChild)
-				splitNode.addChild(string(key[idx]), newChild)
+				splitNode := t.arena.newNode()
+				splitNode.addChild(&t.arena, current.key[split:split+1], oldChild)
+				splitNode.addChild(&t.arena, key[idx:idx+1], newChild)
 
 				current.key = current.key[0:split]
 				current.children = []*node{splitNode}
@@ -273,10 +556,9 @@ func (t *Tree) insert(key string, value interface{}, overwrite bool) bool {
 
 			current = newChild
 		} else {
-			child := &node{
-				key:      current.key[split:],
-				children: current.children,
-			}
+			child := t.arena.newNode()
+			child.key = current.key[split:]
+			child.children = current.children
 
 			current.key = current.key[0:split]
 			current.children = []*node{child}
@@ -290,7 +572,7 @@ func (t *Tree) insert(key string, value interface{}, overwrite bool) bool {
 	for idx < len(key) {
 		var size int
 
-		child := &node{}
+		child := t.arena.newNode()
 
 		// if there are more than one char left and the current key is empty turn it into a compressed node
 		if len(current.key) == 0 && len(key) > 1 {
@@ -300,7 +582,7 @@ func (t *Tree) insert(key string, value interface{}, overwrite bool) bool {
 		} else {
 			size = 1
 
-			current.addChild(string(key[idx]), child)
+			current.addChild(&t.arena, key[idx:idx+1], child)
 		}
 
 		current = child
@@ -339,16 +621,14 @@ func (t *Tree) find(key string, fn func(string, *node) bool) (*node, int, int) {
 			current = current.children[0]
 		} else {
 			// find a child whose key is matching with the lookup key
-			i := sort.Search(len(current.key), func(i int) bool {
-				return current.key[i] >= key[idx]
-			})
-			if i == len(current.key) || current.key[i] != key[idx] {
+			child := current.child(key[idx])
+			if child == nil {
 				// no matching child found - break
 				return current, idx, 0
 			}
 
 			idx += 1
-			current = current.children[i]
+			current = child
 		}
 	}
 
@@ -377,10 +657,11 @@ func (t *Tree) delete(nodes []*node, current *node) {
 			}
 		}
 		if child != nil {
-			current.removeChild(child)
+			current.removeChild(&t.arena, child)
+			t.arena.releaseAll(child)
 		}
 
-		if len(current.children) == 1 && !current.isKey() {
+		if len(current.children) == 1 {
 			trycompress = true
 		}
 	} else if len(current.children) == 1 {
@@ -396,7 +677,7 @@ func (t *Tree) delete(nodes []*node, current *node) {
 			}
 			parent = nodes[len(nodes)-1]
 			nodes = nodes[:len(nodes)-1]
-			if parent.isKey() || (!parent.isCompressed() && len(parent.children) != 1) {
+			if current.isKey() || (!parent.isCompressed() && len(parent.children) != 1) {
 				break
 			}
 			current = parent
@@ -404,51 +685,73 @@ func (t *Tree) delete(nodes []*node, current *node) {
 
 		start := current
 
-		newChild := node{}
+		// merge the chain of nodes with a single child into one compressed node
+		var merged []*node
 		for len(current.children) != 0 {
-			newChild.key += current.key
-			newChild.children = current.children
+			merged = append(merged, current)
 			current = current.children[len(current.children)-1]
 			if current.isKey() || (!current.isCompressed() && len(current.children) != 1) {
 				break
 			}
 		}
-		if newChild.key != "" {
+		if len(merged) > 0 {
+			labels := make([]string, len(merged))
+			for i, n := range merged {
+				labels[i] = n.key
+			}
+
+			newChild := t.arena.newNode()
+			newChild.value = start.value
+			newChild.key = t.arena.label(labels...)
+			newChild.children = merged[len(merged)-1].children
 			if parent != nil {
-				for i, child := range parent.children {
-					if child == start {
-						parent.children[i] = &newChild
-					}
-				}
+				parent.replaceChild(start, newChild)
 			} else {
-				t.root = newChild
+				t.root = *newChild
+				t.arena.release(newChild)
+			}
+
+			for _, n := range merged {
+				if n != &t.root {
+					t.arena.release(n)
+				}
 			}
 		}
 	}
 }
 
-func walk(start *node, fn func(string, *node) bool) {
-	nodes := []*node{start}
-	keys := []string{""}
+// frame is a node on the stack of walk, with the length of the key of its parent and the label of its edge.
+type frame struct {
+	node  *node
+	depth int
+	label string
+}
+
+// walk walks the subtree of a node in lexicographical order. The keys are built in a single buffer starting with the
+// key of the node, which is only valid until fn returns.
+func walk(start *node, buf []byte, fn func([]byte, *node) bool) {
+	frames := []frame{{node: start, depth: len(buf)}}
 
-	for len(nodes) > 0 {
+	for len(frames) > 0 {
 		// pop node
-		current := nodes[len(nodes)-1]
-		nodes = nodes[:len(nodes)-1]
+		current := frames[len(frames)-1]
+		frames = frames[:len(frames)-1]
 
-		// pop key
-		key := keys[len(keys)-1]
-		keys = keys[:len(keys)-1]
+		buf = append(buf[:current.depth], current.label...)
 
 		// call function
-		if fn(key, current) {
+		if fn(buf, current.node) {
 			break
 		}
 
-		// push child nodes
-		nodes = append(nodes, current.getChildren()...)
-
-		// push child keys with current key as prefix
-		keys = append(keys, current.getKeysWithPrefix(key)...)
+		// push child nodes in reverse order to visit them in lexicographical order
+		n := current.node
+		if n.isCompressed() {
+			frames = append(frames, frame{node: n.children[0], depth: len(buf), label: n.key})
+		} else {
+			for i := len(n.children) - 1; i >= 0; i-- {
+				frames = append(frames, frame{node: n.children[i], depth: len(buf), label: n.key[i : i+1]})
+			}
+		}
 	}
 }
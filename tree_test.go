@@ -1,7 +1,9 @@
 package gorax_test
 
 import (
+	"math/rand"
 	"strings"
+	"testing"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -58,6 +60,38 @@ var _ = Describe("Tree", func() {
 			Ω(value).Should(BeNil())
 		})
 	})
+	Context("Reset", func() {
+		It("should_remove_all_entries", func() {
+			t := gorax.FromMap(map[string]interface{}{
+				"bar":    1,
+				"foo":    2,
+				"foobar": 3,
+			}, gorax.WithArena())
+			t.Reset()
+			Ω(t.Len()).Should(BeZero())
+			Ω(t.ToMap()).Should(BeEmpty())
+
+			t.Insert("foo", 4)
+			Ω(t.ToMap()).Should(Equal(map[string]interface{}{"foo": 4}))
+		})
+		It("should_reuse_nodes_of_arena", func() {
+			keys := make([]string, 1000)
+			for i := range keys {
+				keys[i] = randString(rand.Intn(16))
+			}
+
+			allocs := func(opts ...gorax.Option) float64 {
+				t := gorax.New(opts...)
+				return testing.AllocsPerRun(10, func() {
+					t.Reset()
+					for _, key := range keys {
+						t.Insert(key, "")
+					}
+				}) / float64(len(keys))
+			}
+			Ω(allocs(gorax.WithArena())).Should(BeNumerically("<", allocs()))
+		})
+	})
 	Context("DeletePrefix", func() {
 		It("should_not_fail_if_empty", func() {
 			count := gorax.New().DeletePrefix("foo")
@@ -82,6 +116,21 @@ var _ = Describe("Tree", func() {
 			})
 			count := t.DeletePrefix("foo")
 			Ω(count).Should(Equal(3))
+			Ω(t.ToMap()).Should(Equal(map[string]interface{}{"bar": 1}))
+			Ω(t.Len()).Should(Equal(1))
+		})
+		It("should_delete_subtree_of_compressed_node", func() {
+			t := gorax.FromMap(map[string]interface{}{
+				"fo":     1,
+				"foobar": 2,
+			})
+			count := t.DeletePrefix("foo")
+			Ω(count).Should(Equal(1))
+			Ω(t.ToMap()).Should(Equal(map[string]interface{}{"fo": 1}))
+
+			key, _, ok := t.Maximum()
+			Ω(key).Should(Equal("fo"))
+			Ω(ok).Should(BeTrue())
 		})
 	})
 	Context("WalkPrefix", func() {
@@ -144,6 +193,21 @@ var _ = Describe("Tree", func() {
 			Ω(len(actual)).Should(Equal(1))
 			Ω(actual["foo"]).Should(Equal(1))
 		})
+		It("should_walk_prefix_ending_in_compressed_node", func() {
+			t = gorax.FromMap(map[string]interface{}{
+				"fo":     1,
+				"foobar": 2,
+			})
+
+			actual := make(map[string]interface{})
+			t.WalkPrefix("foo", func(key string, value interface{}) bool {
+				actual[key] = value
+
+				return false
+			})
+
+			Ω(actual).Should(Equal(map[string]interface{}{"foobar": 2}))
+		})
 	})
 	Context("WalkPath", func() {
 		var (
@@ -287,4 +351,69 @@ var _ = Describe("Tree", func() {
 			Ω(ok).Should(BeTrue())
 		})
 	})
+	Context("WithSeparator", func() {
+		var (
+			t *gorax.Tree
+		)
+		BeforeEach(func() {
+			t = gorax.FromMap(map[string]interface{}{
+				"/api":          1,
+				"/api/user":     2,
+				"/api/user/1":   3,
+				"/api/users":    4,
+				"/api/users/1":  5,
+				"/api/groups/1": 6,
+				"/apis":         7,
+			}, gorax.WithSeparator('/'))
+		})
+		It("should_match_longest_prefix_on_segment_boundary", func() {
+			key, value, ok := t.LongestPrefix("/api/users")
+			Ω(key).Should(Equal("/api/users"))
+			Ω(value).Should(Equal(4))
+			Ω(ok).Should(BeTrue())
+
+			key, _, ok = t.LongestPrefix("/api/usersettings")
+			Ω(key).Should(Equal("/api"))
+			Ω(ok).Should(BeTrue())
+
+			_, _, ok = t.LongestPrefix("/apiv2")
+			Ω(ok).Should(BeFalse())
+		})
+		It("should_walk_prefix_on_segment_boundary", func() {
+			actual := make(map[string]interface{})
+			t.WalkPrefix("/api/user", func(key string, value interface{}) bool {
+				actual[key] = value
+
+				return false
+			})
+
+			Ω(actual).Should(Equal(map[string]interface{}{"/api/user": 2, "/api/user/1": 3}))
+		})
+		It("should_walk_path_on_segment_boundary", func() {
+			actual := make(map[string]interface{})
+			t.WalkPath("/api/users/1", func(key string, value interface{}) bool {
+				actual[key] = value
+
+				return false
+			})
+
+			Ω(actual).Should(Equal(map[string]interface{}{"/api": 1, "/api/users": 4, "/api/users/1": 5}))
+		})
+		It("should_delete_prefix_on_segment_boundary", func() {
+			Ω(t.DeletePrefix("/api/user")).Should(Equal(2))
+			Ω(t.Len()).Should(Equal(5))
+
+			_, ok := t.Get("/api/users")
+			Ω(ok).Should(BeTrue())
+		})
+		It("should_list_children", func() {
+			Ω(t.Children("/api")).Should(Equal([]string{"groups", "user", "users"}))
+			Ω(t.Children("/api/")).Should(Equal([]string{"groups", "user", "users"}))
+			Ω(t.Children("/")).Should(Equal([]string{"api", "apis"}))
+			Ω(t.Children("/foo")).Should(BeEmpty())
+		})
+		It("should_not_list_children_without_separator", func() {
+			Ω(gorax.New().Children("/api")).Should(BeNil())
+		})
+	})
 })
@@ -0,0 +1,137 @@
+package gorax
+
+import (
+	"container/heap"
+	"sync"
+	"time"
+)
+
+// EvictFn is called with the key and value of an entry removed by the Tree itself, e.g. because it expired.
+type EvictFn func(key string, value interface{})
+
+// InsertWithTTL is like Insert, but the entry expires after the ttl. Expired entries are invisible to all operations
+// and are removed from the Tree through Delete, calling the EvictFn of the Tree.
+func (t *Tree) InsertWithTTL(key string, value interface{}, ttl time.Duration) bool {
+	if value == nil {
+		value = Nil{}
+	}
+
+	deadline := t.now().Add(ttl)
+
+	ok := t.Insert(key, expiring{value: value, deadline: deadline})
+	heap.Push(&t.deadlines, expiry{key: t.normalizeKey(key), deadline: deadline})
+
+	return ok
+}
+
+// Sweep removes all expired entries and returns how many were removed. Expired entries are removed lazily by every
+// operation on the Tree anyway, Sweep can be used to release their memory without accessing the Tree.
+func (t *Tree) Sweep() int {
+	var counter int
+	if len(t.deadlines) == 0 {
+		return counter
+	}
+
+	now := t.now()
+	for len(t.deadlines) > 0 && !t.deadlines[0].deadline.After(now) {
+		e := heap.Pop(&t.deadlines).(expiry)
+
+		// skip entries which were deleted or updated in the meantime
+		current := t.lookup(e.key)
+		if current == nil {
+			continue
+		}
+		if v, ok := current.value.(expiring); !ok || !v.deadline.Equal(e.deadline) {
+			continue
+		}
+
+		original, value, _ := t.remove(e.key)
+		if t.evict != nil {
+			t.evict(original, value)
+		}
+		counter += 1
+	}
+
+	return counter
+}
+
+// StartSweeper calls Sweep every interval while holding the lock, which must also guard all other accesses to the
+// Tree. Returns a function to stop the sweeper.
+func (t *Tree) StartSweeper(interval time.Duration, lock sync.Locker) (stop func()) {
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				lock.Lock()
+				t.Sweep()
+				lock.Unlock()
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			ticker.Stop()
+			close(done)
+		})
+	}
+}
+
+// expire removes the expired entries before an operation.
+func (t *Tree) expire() {
+	if len(t.deadlines) > 0 {
+		t.Sweep()
+	}
+}
+
+func (t *Tree) now() time.Time {
+	if t.clock != nil {
+		return t.clock()
+	}
+
+	return time.Now()
+}
+
+// expiring wraps the value of an entry inserted with a TTL.
+type expiring struct {
+	value    interface{}
+	deadline time.Time
+}
+
+type expiry struct {
+	key      string
+	deadline time.Time
+}
+
+// deadlines is a min-heap of the expiries ordered by deadline.
+type deadlines []expiry
+
+func (d deadlines) Len() int {
+	return len(d)
+}
+
+func (d deadlines) Less(i, j int) bool {
+	return d[i].deadline.Before(d[j].deadline)
+}
+
+func (d deadlines) Swap(i, j int) {
+	d[i], d[j] = d[j], d[i]
+}
+
+func (d *deadlines) Push(x interface{}) {
+	*d = append(*d, x.(expiry))
+}
+
+func (d *deadlines) Pop() interface{} {
+	old := *d
+	ret := old[len(old)-1]
+	*d = old[:len(old)-1]
+
+	return ret
+}
@@ -0,0 +1,111 @@
+package gorax_test
+
+import (
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/snorwin/gorax"
+)
+
+var _ = Describe("Tree", func() {
+	Context("InsertWithTTL", func() {
+		var (
+			t       *gorax.Tree
+			now     time.Time
+			evicted map[string]interface{}
+		)
+		BeforeEach(func() {
+			now = time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+			evicted = map[string]interface{}{}
+
+			t = gorax.New(
+				gorax.WithClock(func() time.Time {
+					return now
+				}),
+				gorax.WithEvictFn(func(key string, value interface{}) {
+					evicted[key] = value
+				}),
+			)
+			t.Insert("foo", 1)
+			t.InsertWithTTL("foobar", 2, time.Minute)
+			t.InsertWithTTL("foobarfoo", nil, time.Hour)
+		})
+		It("should_get_entry_before_expiry", func() {
+			now = now.Add(59 * time.Second)
+
+			value, ok := t.Get("foobar")
+			Ω(ok).Should(BeTrue())
+			Ω(value).Should(Equal(2))
+			Ω(t.Len()).Should(Equal(3))
+			Ω(evicted).Should(BeEmpty())
+		})
+		It("should_hide_expired_entries", func() {
+			now = now.Add(time.Minute)
+
+			_, ok := t.Get("foobar")
+			Ω(ok).Should(BeFalse())
+
+			key, _, ok := t.LongestPrefix("foobarbar")
+			Ω(ok).Should(BeTrue())
+			Ω(key).Should(Equal("foo"))
+
+			Ω(t.ToMap()).Should(Equal(map[string]interface{}{"foo": 1, "foobarfoo": nil}))
+			Ω(t.Len()).Should(Equal(2))
+			Ω(evicted).Should(Equal(map[string]interface{}{"foobar": 2}))
+		})
+		It("should_keep_updated_entries", func() {
+			t.Insert("foobar", 3)
+			t.InsertWithTTL("foo", 4, 2*time.Minute)
+
+			now = now.Add(time.Minute)
+
+			Ω(t.ToMap()).Should(Equal(map[string]interface{}{"foo": 4, "foobar": 3, "foobarfoo": nil}))
+			Ω(evicted).Should(BeEmpty())
+
+			now = now.Add(time.Minute)
+
+			Ω(t.ToMap()).Should(Equal(map[string]interface{}{"foobar": 3, "foobarfoo": nil}))
+			Ω(evicted).Should(Equal(map[string]interface{}{"foo": 4}))
+		})
+		It("should_insert_over_expired_entry", func() {
+			now = now.Add(time.Minute)
+
+			Ω(t.Insert("foobar", 3)).Should(BeTrue())
+			Ω(t.Len()).Should(Equal(3))
+		})
+		It("should_sweep_expired_entries", func() {
+			now = now.Add(time.Hour)
+
+			Ω(t.Sweep()).Should(Equal(2))
+			Ω(t.Sweep()).Should(Equal(0))
+			Ω(t.ToMap()).Should(Equal(map[string]interface{}{"foo": 1}))
+			Ω(evicted).Should(Equal(map[string]interface{}{"foobar": 2, "foobarfoo": nil}))
+
+			key, _, ok := t.Maximum()
+			Ω(ok).Should(BeTrue())
+			Ω(key).Should(Equal("foo"))
+		})
+		It("should_sweep_periodically", func() {
+			var lock sync.Mutex
+
+			lock.Lock()
+			now = now.Add(time.Hour)
+			lock.Unlock()
+
+			stop := t.StartSweeper(time.Millisecond, &lock)
+			defer stop()
+
+			Eventually(func() int {
+				lock.Lock()
+				defer lock.Unlock()
+
+				return len(evicted)
+			}).Should(Equal(2))
+
+			stop()
+		})
+	})
+})
@@ -0,0 +1,118 @@
+package gorax
+
+import (
+	"container/heap"
+	"strings"
+)
+
+// Tx is a transaction of a Tree started by Begin. It journals the previous values of all keys changed while it is
+// open, i.e. also by evictions and expirations, to roll the Tree back to the start of the transaction or a Savepoint.
+type Tx struct {
+	tree    *Tree
+	journal []change
+}
+
+// Savepoint is a position in the journal of a Tx to roll back to.
+type Savepoint int
+
+// change is the previous value of a normalized key, nil if the key was not in the Tree.
+type change struct {
+	key   string
+	value interface{}
+}
+
+// Begin starts a transaction of the Tree. Only one transaction can be open at a time, Begin panics if there is one
+// already.
+func (t *Tree) Begin() *Tx {
+	if t.tx != nil {
+		panic("gorax: transaction already open")
+	}
+
+	t.tx = &Tx{tree: t}
+
+	return t.tx
+}
+
+// Insert is like Insert of the Tree.
+func (tx *Tx) Insert(key string, value interface{}) bool {
+	return tx.tree.Insert(key, value)
+}
+
+// Delete is like Delete of the Tree.
+func (tx *Tx) Delete(key string) (interface{}, bool) {
+	return tx.tree.Delete(key)
+}
+
+// DeletePrefix is like DeletePrefix of the Tree.
+func (tx *Tx) DeletePrefix(prefix string) int {
+	return tx.tree.DeletePrefix(prefix)
+}
+
+// Savepoint returns a Savepoint of the current state of the Tree.
+func (tx *Tx) Savepoint() Savepoint {
+	return Savepoint(len(tx.journal))
+}
+
+// RollbackTo restores the keys and values of the Tree at a Savepoint, the transaction stays open. Restored keys with a
+// TTL keep their deadline.
+func (tx *Tx) RollbackTo(sp Savepoint) {
+	if !tx.open() || int(sp) > len(tx.journal) {
+		return
+	}
+
+	t := tx.tree
+	t.tx = nil
+	for i := len(tx.journal) - 1; i >= int(sp); i-- {
+		c := tx.journal[i]
+		if c.value == nil {
+			t.remove(c.key)
+			continue
+		}
+
+		if t.insert(c.key, c.value, true) {
+			t.size += 1
+		}
+		t.update()
+		if e, ok := c.value.(expiring); ok {
+			heap.Push(&t.deadlines, expiry{key: c.key, deadline: e.deadline})
+		}
+		if len(t.bounds) > 0 {
+			t.touch(c.key)
+		}
+	}
+	t.tx = tx
+
+	tx.journal = tx.journal[:sp]
+}
+
+// Rollback restores the keys and values of the Tree at the start of the transaction and closes it.
+func (tx *Tx) Rollback() {
+	tx.RollbackTo(0)
+	tx.Commit()
+}
+
+// Commit keeps the changes and closes the transaction.
+func (tx *Tx) Commit() {
+	if tx.open() {
+		tx.tree.tx = nil
+	}
+	tx.journal = nil
+}
+
+func (tx *Tx) open() bool {
+	return tx.tree.tx == tx
+}
+
+// record journals the previous value of a normalized key before it is changed, the node is nil if the key is not in the
+// Tree. The key is copied, as it may point into a buffer like the one of DeleteBytes.
+func (t *Tree) record(key string, n *node) {
+	if t.tx == nil {
+		return
+	}
+
+	var value interface{}
+	if n != nil {
+		value = n.value
+	}
+	t.tx.journal = append(t.tx.journal, change{key: strings.Clone(key), value: value})
+}
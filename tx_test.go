@@ -0,0 +1,191 @@
+package gorax_test
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/snorwin/gorax"
+)
+
+var _ = Describe("Tx", func() {
+	var (
+		t *gorax.Tree
+	)
+	BeforeEach(func() {
+		t = gorax.FromMap(map[string]interface{}{"foo": 1, "foobar": 2, "bar": nil})
+	})
+	It("should_rollback", func() {
+		tx := t.Begin()
+		Ω(tx.Insert("foo", 3)).Should(BeFalse())
+		Ω(tx.Insert("jin", 4)).Should(BeTrue())
+		_, ok := tx.Delete("bar")
+		Ω(ok).Should(BeTrue())
+		Ω(tx.DeletePrefix("foo")).Should(Equal(2))
+		Ω(t.Len()).Should(Equal(1))
+
+		tx.Rollback()
+		Ω(t.Len()).Should(Equal(3))
+		Ω(t.ToMap()).Should(Equal(map[string]interface{}{"foo": 1, "foobar": 2, "bar": nil}))
+	})
+	It("should_commit", func() {
+		tx := t.Begin()
+		tx.Insert("jin", 4)
+		tx.Delete("foo")
+		tx.Commit()
+
+		tx.Rollback()
+		Ω(t.ToMap()).Should(Equal(map[string]interface{}{"foobar": 2, "bar": nil, "jin": 4}))
+
+		// a new transaction can be started after the commit
+		t.Begin().Rollback()
+	})
+	It("should_rollback_to_savepoint", func() {
+		tx := t.Begin()
+		tx.Insert("jin", 4)
+		sp := tx.Savepoint()
+		tx.Insert("jin", 5)
+		tx.DeletePrefix("")
+		Ω(t.Len()).Should(BeZero())
+
+		tx.RollbackTo(sp)
+		Ω(t.ToMap()).Should(Equal(map[string]interface{}{"foo": 1, "foobar": 2, "bar": nil, "jin": 4}))
+
+		tx.Delete("foo")
+		tx.RollbackTo(sp)
+		Ω(t.Len()).Should(Equal(4))
+
+		tx.Rollback()
+		Ω(t.ToMap()).Should(Equal(map[string]interface{}{"foo": 1, "foobar": 2, "bar": nil}))
+	})
+	It("should_journal_mutations_of_tree", func() {
+		tx := t.Begin()
+		t.Insert("jin", 4)
+		t.Delete("foobar")
+
+		tx.Rollback()
+		Ω(t.ToMap()).Should(Equal(map[string]interface{}{"foo": 1, "foobar": 2, "bar": nil}))
+	})
+	It("should_rollback_delete_bytes", func() {
+		key := []byte("foobar")
+		tx := t.Begin()
+		_, ok := t.DeleteBytes(key)
+		Ω(ok).Should(BeTrue())
+		copy(key, "barfoo")
+
+		tx.Rollback()
+		Ω(t.ToMap()).Should(Equal(map[string]interface{}{"foo": 1, "foobar": 2, "bar": nil}))
+	})
+	It("should_rollback_reset", func() {
+		for _, t := range []*gorax.Tree{gorax.New(), gorax.New(gorax.WithArena())} {
+			t.Insert("a", 1)
+			t.Insert("b", 2)
+
+			tx := t.Begin()
+			t.Insert("a", 10)
+			t.Reset()
+			t.Insert("c", 3)
+			Ω(t.Len()).Should(Equal(1))
+
+			tx.Rollback()
+			Ω(t.Len()).Should(Equal(2))
+			Ω(t.ToMap()).Should(Equal(map[string]interface{}{"a": 1, "b": 2}))
+		}
+	})
+	It("should_not_begin_nested_transaction", func() {
+		t.Begin()
+		Ω(func() { t.Begin() }).Should(Panic())
+	})
+	It("should_restore_evicted_keys", func() {
+		t := gorax.New(gorax.WithCapacity(2))
+		t.Insert("foo", 1)
+		t.Insert("bar", 2)
+
+		tx := t.Begin()
+		t.Insert("jin", 3)
+		t.Insert("foobar", 4)
+		Ω(t.ToMap()).Should(Equal(map[string]interface{}{"jin": 3, "foobar": 4}))
+
+		tx.Rollback()
+		Ω(t.ToMap()).Should(Equal(map[string]interface{}{"foo": 1, "bar": 2}))
+
+		t.Insert("jin", 3)
+		Ω(t.Len()).Should(Equal(2))
+	})
+	It("should_restore_deadlines", func() {
+		now := time.Now()
+		t := gorax.New(gorax.WithClock(func() time.Time { return now }))
+		t.InsertWithTTL("foo", 1, time.Minute)
+
+		tx := t.Begin()
+		t.Delete("foo")
+		tx.Rollback()
+		Ω(t.Len()).Should(Equal(1))
+
+		now = now.Add(time.Hour)
+		Ω(t.Len()).Should(BeZero())
+	})
+	It("should_restore_aggregates_and_normalized_keys", func() {
+		t := gorax.New(gorax.WithAggregate(sum{}), gorax.WithNormalizer(gorax.CaseFold))
+		t.Insert("Foo", 1)
+		t.Insert("FooBar", 2)
+
+		tx := t.Begin()
+		t.Insert("foo", 3)
+		t.DeletePrefix("FOOB")
+		Ω(t.Aggregate("")).Should(Equal(3))
+
+		tx.Rollback()
+		Ω(t.Aggregate("")).Should(Equal(3))
+		Ω(t.ToMap()).Should(Equal(map[string]interface{}{"Foo": 1, "FooBar": 2}))
+	})
+	It("should_rollback_random_mutations", func() {
+		t := gorax.New(gorax.WithSeparator('/'))
+		for i := 0; i < 100; i++ {
+			t.Insert(randPath(), i)
+		}
+		before := t.ToMap()
+
+		tx := t.Begin()
+		var savepoints []gorax.Savepoint
+		var states []map[string]interface{}
+		for i := 0; i < 200; i++ {
+			if i%20 == 0 {
+				savepoints = append(savepoints, tx.Savepoint())
+				states = append(states, t.ToMap())
+			}
+
+			switch rand.Intn(3) {
+			case 0:
+				tx.Insert(randPath(), i)
+			case 1:
+				tx.Delete(randPath())
+			default:
+				tx.DeletePrefix(randPath()[:2])
+			}
+		}
+
+		for i := len(savepoints) - 1; i >= 0; i-- {
+			tx.RollbackTo(savepoints[i])
+			Ω(t.ToMap()).Should(Equal(states[i]))
+			Ω(t.Len()).Should(Equal(len(states[i])))
+		}
+
+		tx.Rollback()
+		Ω(t.ToMap()).Should(Equal(before))
+		Ω(t.Len()).Should(Equal(len(before)))
+	})
+})
+
+// randPath returns a random path of up to three segments of a small alphabet.
+func randPath() string {
+	var path string
+	for i := rand.Intn(3); i >= 0; i-- {
+		path += fmt.Sprintf("/%c", 'a'+rand.Intn(4))
+	}
+
+	return path
+}
@@ -0,0 +1,65 @@
+package gorax
+
+import "fmt"
+
+// Validate checks the invariants of the shape of the Tree and returns an error describing the first violation found,
+// or nil if the Tree is valid. It is meant for debugging and testing, e.g. after every mutation in a fuzzy test.
+func (t *Tree) Validate() error {
+	var keys int
+
+	var visit func(n *node, key []byte, single bool) error
+	visit = func(n *node, key []byte, single bool) error {
+		if n.isKey() {
+			keys += 1
+		}
+
+		if n.isCompressed() {
+			// a compressed node has a label of at least two bytes to a single child
+			if len(n.children) != 1 || len(n.key) < 2 {
+				return fmt.Errorf("gorax: compressed node %q has label %q to %d children", key, n.key, len(n.children))
+			}
+		} else {
+			for i := 1; i < len(n.key); i++ {
+				if n.key[i-1] >= n.key[i] {
+					return fmt.Errorf("gorax: branching node %q has unsorted child bytes %q", key, n.key)
+				}
+			}
+			for i := range n.children {
+				if n.child(n.key[i]) != n.children[i] {
+					return fmt.Errorf("gorax: branching node %q does not find its child %q", key, n.key[i:i+1])
+				}
+			}
+		}
+
+		if n != &t.root {
+			if len(n.children) == 0 && !n.isKey() {
+				return fmt.Errorf("gorax: leaf %q is not a key", key)
+			}
+			// the only child of a node is merged with its own only child, unless it is a key
+			if single && len(n.children) == 1 && !n.isKey() {
+				return fmt.Errorf("gorax: node %q is not compressed with its only child", key)
+			}
+		}
+
+		for i, child := range n.children {
+			label := n.key
+			if !n.isCompressed() {
+				label = n.key[i : i+1]
+			}
+			if err := visit(child, append(key, label...), len(n.children) == 1); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+	if err := visit(&t.root, nil, false); err != nil {
+		return err
+	}
+
+	if keys != t.size {
+		return fmt.Errorf("gorax: size %d differs from the number of keys %d", t.size, keys)
+	}
+
+	return nil
+}
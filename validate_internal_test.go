@@ -0,0 +1,52 @@
+package gorax
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Tree", func() {
+	Context("Validate", func() {
+		var (
+			t *Tree
+		)
+		BeforeEach(func() {
+			// "" -[b,f]-> ("b" -"ar"-> "bar", "f" -"oo"-> "foo" -"bar"-> "foobar")
+			t = FromMap(map[string]interface{}{
+				"foo":    1,
+				"foobar": 2,
+				"bar":    3,
+			})
+			Ω(t.root.key).Should(Equal("bf"))
+			Ω(t.Validate()).Should(Succeed())
+		})
+		It("should_fail_on_unsorted_child_bytes", func() {
+			t.root.key = "fb"
+			t.root.children[0], t.root.children[1] = t.root.children[1], t.root.children[0]
+			Ω(t.Validate()).Should(MatchError(ContainSubstring("unsorted child bytes")))
+		})
+		It("should_fail_on_compressed_node_with_short_label", func() {
+			t.root.children[0].children[0].key = "x"
+			Ω(t.Validate()).Should(MatchError(ContainSubstring("compressed node")))
+		})
+		It("should_fail_on_compressed_node_with_many_children", func() {
+			foo := t.root.children[1].children[0]
+			foo.children = append(foo.children, &node{value: 4})
+			Ω(t.Validate()).Should(MatchError(ContainSubstring("compressed node")))
+		})
+		It("should_fail_on_uncompressed_chain", func() {
+			f := t.root.children[1]
+			f.key = "o"
+			f.children = []*node{{key: "o", children: f.children}}
+			Ω(t.Validate()).Should(MatchError(ContainSubstring("not compressed")))
+		})
+		It("should_fail_on_leaf_without_key", func() {
+			t.root.children[0].children[0].value = nil
+			Ω(t.Validate()).Should(MatchError(ContainSubstring("is not a key")))
+		})
+		It("should_fail_on_wrong_size", func() {
+			t.size += 1
+			Ω(t.Validate()).Should(MatchError(ContainSubstring("size")))
+		})
+	})
+})
@@ -0,0 +1,44 @@
+package gorax_test
+
+import (
+	"math/rand"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/snorwin/gorax"
+)
+
+var _ = Describe("Tree", func() {
+	Context("Validate", func() {
+		It("should_validate_empty_tree", func() {
+			Ω(gorax.New().Validate()).Should(Succeed())
+		})
+		It("should_validate_after_mutations", func() {
+			for _, opts := range [][]gorax.Option{
+				{},
+				{gorax.WithSeparator('/')},
+				{gorax.WithArena()},
+				{gorax.WithNormalizer(gorax.CaseFold), gorax.WithCapacity(50)},
+			} {
+				t := gorax.New(opts...)
+				tx := t.Begin()
+				for i := 0; i < 500; i++ {
+					switch rand.Intn(4) {
+					case 0:
+						t.Delete(randPath())
+					case 1:
+						t.DeletePrefix(randPath()[:2])
+					default:
+						t.Insert(randPath(), i)
+					}
+					Ω(t.Validate()).Should(Succeed())
+				}
+
+				tx.Rollback()
+				Ω(t.Validate()).Should(Succeed())
+				Ω(t.Len()).Should(BeZero())
+			}
+		})
+	})
+})